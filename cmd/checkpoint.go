@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"get-node-start-block/pkg/endpoint"
+)
+
+// Statuses of the networks of a checkpoint.
+const (
+	checkpointResolved = "resolved"
+	checkpointPending  = "pending"
+)
+
+// checkpointState is the progress of a resolve run saved to its checkpoint file, so that a run dying halfway,
+// like on a flaky network, can be resumed with --resume rather than started over.
+type checkpointState struct {
+	// Config is the absolute path of the config the run resolves the networks of.
+	Config string `json:"config"`
+	// Timestamp is the target as given to the run, which may be relative to the time of the run.
+	Timestamp string `json:"timestamp"`
+	// Target is the timestamp Timestamp stood for when the run started, which resumed runs keep.
+	Target      int64                        `json:"target"`
+	Direction   string                       `json:"direction"`
+	FromGenesis bool                         `json:"from_genesis,omitempty"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+	Networks    map[string]checkpointNetwork `json:"networks"`
+}
+
+// checkpointNetwork is the progress of a single network in a checkpoint.
+type checkpointNetwork struct {
+	Status string `json:"status"`
+	// Block and the fields after it are only set for resolved networks.
+	Block          int64   `json:"block,omitempty"`
+	BlockTimestamp int64   `json:"block_timestamp,omitempty"`
+	Target         int64   `json:"target,omitempty"`
+	Genesis        bool    `json:"genesis,omitempty"`
+	Deployment     bool    `json:"deployment,omitempty"`
+	BlockTime      float64 `json:"block_time,omitempty"`
+	DurationMS     int64   `json:"duration_ms,omitempty"`
+	// Endpoints are the endpoints that answered the calls of the network, kept for the provenance of its block.
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Error is why the network isn't resolved yet, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// checkpoint saves the progress of a resolve run to a file as every network is resolved. Its methods do nothing
+// on a nil checkpoint, so that runs without a checkpoint file resolve networks all the same.
+type checkpoint struct {
+	path string
+
+	locker sync.Mutex
+	state  checkpointState
+}
+
+// newCheckpoint creates a checkpoint saving the progress of a run resolving the networks of the config at
+// configPath for targetTimestamp to path, or nil if path is empty. Nothing is saved until a network is recorded.
+func newCheckpoint(path, configPath, timestamp string, targetTimestamp int64, direction string, fromGenesis bool) *checkpoint {
+	if path == "" {
+		return nil
+	}
+
+	return &checkpoint{
+		path: path,
+		state: checkpointState{
+			Config:      absolutePath(configPath),
+			Timestamp:   timestamp,
+			Target:      targetTimestamp,
+			Direction:   direction,
+			FromGenesis: fromGenesis,
+			Networks:    make(map[string]checkpointNetwork),
+		},
+	}
+}
+
+// loadCheckpoint reads the checkpoint file at path, returning nil if there is none.
+func loadCheckpoint(path string) (*checkpointState, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("error parsing checkpoint %s: %w", path, err)
+	}
+
+	return &state, nil
+}
+
+// resumes returns why a run of c can't resume from previous, or an empty string if it can: the previous run must
+// have resolved the same config for the same target, direction and genesis setting.
+func (c *checkpoint) resumes(previous *checkpointState) string {
+	switch {
+	case previous.Config != c.state.Config:
+		return fmt.Sprintf("it is for config %s", previous.Config)
+	case previous.Timestamp != c.state.Timestamp:
+		return fmt.Sprintf("it is for target %q", previous.Timestamp)
+	case previous.Direction != c.state.Direction || previous.FromGenesis != c.state.FromGenesis:
+		return "it was resolved with another direction or --from-genesis"
+	default:
+		return ""
+	}
+}
+
+// resume takes over the networks resolved by previous, returning their results. The target of the run becomes the
+// one of previous, as relative targets like now-30d moved since.
+func (c *checkpoint) resume(previous *checkpointState, networks []Network) []resolution {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.state.Target = previous.Target
+
+	var results []resolution
+
+	for _, network := range networks {
+		saved, ok := previous.Networks[network.Name]
+		if !ok || saved.Status != checkpointResolved {
+			continue
+		}
+
+		c.state.Networks[network.Name] = saved
+
+		result := resolution{
+			Network:        network,
+			Target:         saved.Target,
+			Genesis:        saved.Genesis,
+			Deployment:     saved.Deployment,
+			Block:          saved.Block,
+			BlockTimestamp: saved.BlockTimestamp,
+			BlockTime:      saved.BlockTime,
+			Duration:       time.Duration(saved.DurationMS) * time.Millisecond,
+		}
+
+		for _, url := range saved.Endpoints {
+			result.Endpoints = append(result.Endpoints, endpoint.Health{URL: url, Successes: 1})
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// record saves the outcome of resolving a network to the checkpoint file. Failing to save it only loses the
+// ability to resume, so it is logged rather than failing the network.
+func (c *checkpoint) record(result resolution) {
+	if c == nil {
+		return
+	}
+
+	saved := checkpointNetwork{Status: checkpointPending}
+
+	if result.Err != nil {
+		saved.Error = result.Err.Error()
+	} else {
+		saved = checkpointNetwork{
+			Status:         checkpointResolved,
+			Block:          result.Block,
+			BlockTimestamp: result.BlockTimestamp,
+			Target:         result.Target,
+			Genesis:        result.Genesis,
+			Deployment:     result.Deployment,
+			BlockTime:      result.BlockTime,
+			DurationMS:     result.Duration.Milliseconds(),
+		}
+
+		for _, health := range result.Endpoints {
+			if health.Successes > 0 {
+				saved.Endpoints = append(saved.Endpoints, health.URL)
+			}
+		}
+	}
+
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.state.Networks[result.Network.Name] = saved
+	c.state.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	if err := c.save(); err != nil {
+		slog.Warn("Error saving checkpoint", "path", c.path, "error", err)
+	}
+}
+
+// save writes the state of the checkpoint to its file. The checkpoint must be locked.
+func (c *checkpoint) save() error {
+	content, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("error creating checkpoint directory: %w", err)
+	}
+
+	return writeFile(c.path, content)
+}
+
+// clear removes the checkpoint file once the run it saves is complete, as there is nothing left to resume.
+func (c *checkpoint) clear() {
+	if c == nil {
+		return
+	}
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("Error removing checkpoint", "path", c.path, "error", err)
+	}
+}
+
+// defaultCheckpointPath returns the path of the checkpoint file in the user cache directory, or an empty path,
+// disabling checkpoints, if there is no such directory.
+func defaultCheckpointPath() string {
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(directory, "get-node-start-block", "checkpoint.json")
+}
+
+// absolutePath returns the absolute form of path, or path itself if it has none.
+func absolutePath(path string) string {
+	if absolute, err := filepath.Abs(path); err == nil {
+		return absolute
+	}
+
+	return path
+}
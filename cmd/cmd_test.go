@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"get-node-start-block/pkg/mockchain"
+)
+
+// runCommand runs the command line args, with every flag back to its default as on a fresh process.
+func runCommand(t *testing.T, args ...string) error {
+	t.Helper()
+
+	resetFlags(rootCmd)
+	activeFixtures = nil
+
+	rootCmd.SetArgs(args)
+
+	return rootCmd.ExecuteContext(context.Background())
+}
+
+// resetFlags sets every flag of command and its subcommands back to its default.
+func resetFlags(command *cobra.Command) {
+	for _, flags := range []*pflag.FlagSet{command.PersistentFlags(), command.Flags()} {
+		flags.VisitAll(func(flag *pflag.Flag) {
+			if slice, ok := flag.Value.(pflag.SliceValue); ok {
+				_ = slice.Replace(nil)
+			} else {
+				_ = flag.Value.Set(flag.DefValue)
+			}
+
+			flag.Changed = false
+		})
+	}
+
+	for _, child := range command.Commands() {
+		resetFlags(child)
+	}
+}
+
+// writeFixtures writes a fixtures file serving the networks of chains, and every other network from the default
+// chain, returning its path.
+func writeFixtures(t *testing.T, chains map[string]mockchain.Chain) string {
+	t.Helper()
+
+	return writeJSON(t, "fixtures.json", fixtures{Default: mockchain.DefaultChain, Networks: chains})
+}
+
+// writeJSON writes value as JSON to name in a temporary directory of the test, returning its path.
+func writeJSON(t *testing.T, name string, value interface{}) string {
+	t.Helper()
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("error marshaling %s: %v", name, err)
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("error writing %s: %v", name, err)
+	}
+
+	return path
+}
+
+// readJSON reads the JSON file at path into value.
+func readJSON(t *testing.T, path string, value interface{}) {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(content, value); err != nil {
+		t.Fatalf("error parsing %s: %v", path, err)
+	}
+}
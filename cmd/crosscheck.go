@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+	"get-node-start-block/pkg/provider"
+)
+
+// CrossCheckSource is a second source of the blocks of a network, which resolved start blocks are checked against
+// so that a single lying or buggy endpoint can't pick them.
+type CrossCheckSource struct {
+	// Type is how the source is read: "etherscan" for Etherscan-family APIs, "blockscout" for Blockscout explorers,
+	// or any network type, like the one of the network for a second RPC endpoint. It defaults to the network type.
+	Type string `json:"type,omitempty"`
+	// URL is the URL of the source, which may reference environment variables holding API keys, like
+	// "https://api.etherscan.io/api?apikey=${ETHERSCAN_API_KEY}". Sources referencing unset variables are skipped.
+	URL string `json:"url"`
+}
+
+// validCrossCheck returns an error if a cross-check source of a network is unusable.
+func validCrossCheck(source CrossCheckSource) error {
+	if source.URL == "" {
+		return fmt.Errorf("cross_check source needs a url")
+	}
+
+	if source.Type != "" && !slices.Contains(provider.Types(), source.Type) {
+		return fmt.Errorf("cross_check source has unsupported type %q, expected one of %s", source.Type, strings.Join(provider.Types(), ", "))
+	}
+
+	return nil
+}
+
+// crossCheck checks the timestamp of block against the cross-check sources of network, failing when a source
+// disagrees with the endpoints of the network. Sources that can't be reached are logged and skipped, as they
+// only add confidence, and networks without any are left unchecked.
+func crossCheck(ctx context.Context, network Network, block blockfinder.BlockRef) error {
+	for _, source := range network.CrossCheck {
+		url, ok := expandEndpoint(source.URL)
+		if !ok {
+			slog.Debug("Skipping cross-check source referencing unset variables", "network", network.Name)
+			continue
+		}
+
+		checked := Network{
+			Name:      network.Name + " cross-check",
+			Type:      source.Type,
+			URLs:      []string{url},
+			RateLimit: network.RateLimit,
+			Proxy:     network.Proxy,
+		}
+		if checked.Type == "" {
+			checked.Type = network.Type
+		}
+
+		logger := slog.With("network", network.Name, "block", block.Number, "source", endpoint.Redact(url))
+
+		timestamp, err := crossCheckedTimestamp(ctx, checked, block.Number)
+		if err != nil {
+			logger.Warn("Error cross-checking start block, skipping source", "error", err)
+			continue
+		}
+
+		if timestamp != block.Timestamp {
+			return fmt.Errorf("block %d has timestamp %d on the endpoints but %d on cross-check source %s, one of them is wrong",
+				block.Number, block.Timestamp, timestamp, endpoint.Redact(url))
+		}
+
+		logger.Debug("Start block cross-checked")
+	}
+
+	return nil
+}
+
+// crossCheckedTimestamp returns the timestamp of the block at number on the source described by network.
+func crossCheckedTimestamp(ctx context.Context, network Network, number int64) (int64, error) {
+	source, conn, err := dialNetwork(ctx, network)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return source.BlockTimestamp(ctx, number)
+}
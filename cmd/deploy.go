@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/blockfinder"
+)
+
+var deployBlockCmd = &cobra.Command{
+	Use:   "deploy-block",
+	Short: "Print the block a contract was deployed in",
+	Long: `Print the block a contract was deployed in, with its timestamp as Unix seconds and as an RFC3339 date.
+The block is found by bisecting eth_getCode over the history of the network, which needs an archive node.
+Set a network's anchor_contract for its start blocks to never be before that block.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("network")
+		address, _ := cmd.Flags().GetString("address")
+
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid contract address %q", address)
+		}
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		network, err := lookupNetwork(config, name)
+		if err != nil {
+			return err
+		}
+
+		source, conn, err := dialNetwork(cmd.Context(), network)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if err := verifyChainID(cmd.Context(), network, source); err != nil {
+			return err
+		}
+
+		deployer, ok := source.(blockfinder.DeploymentFinder)
+		if !ok {
+			return fmt.Errorf("network type %q has no contracts", network.Type)
+		}
+
+		deployed, err := deployer.DeploymentBlock(cmd.Context(), address)
+		if err != nil {
+			return fmt.Errorf("error finding the deployment of %s: %w", address, err)
+		}
+
+		fmt.Printf("%d\t%d\t%s\n", deployed.Number, deployed.Timestamp, time.Unix(deployed.Timestamp, 0).UTC().Format(time.RFC3339))
+
+		return nil
+	},
+}
+
+func init() {
+	deployBlockCmd.Flags().String("network", "", "name of the network, as in the config or the networks of the profile")
+	deployBlockCmd.Flags().String("address", "", "address of the contract")
+	_ = deployBlockCmd.MarkFlagRequired("network")
+	_ = deployBlockCmd.MarkFlagRequired("address")
+
+	rootCmd.AddCommand(deployBlockCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-config> <new-config>",
+	Short: "Compare the start blocks of two config files",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldConfig, err := loadConfig(args[0])
+		if err != nil {
+			return err
+		}
+
+		newConfig, err := loadConfig(args[1])
+		if err != nil {
+			return err
+		}
+
+		changes := diffStartBlocks(oldConfig.StartBlockNumbers(), newConfig.StartBlockNumbers())
+		if len(changes) == 0 {
+			fmt.Println("No differences.")
+			return nil
+		}
+
+		printChanges(changes)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// diffStartBlocks describes every network whose start block differs between oldBlocks and newBlocks,
+// sorted by network name.
+func diffStartBlocks(oldBlocks, newBlocks map[string]int64) []string {
+	names := make(map[string]struct{})
+	for name := range oldBlocks {
+		names[name] = struct{}{}
+	}
+	for name := range newBlocks {
+		names[name] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+
+	for _, name := range sorted {
+		oldBlock, inOld := oldBlocks[name]
+		newBlock, inNew := newBlocks[name]
+
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("+ %s: %d", name, newBlock))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("- %s: %d", name, oldBlock))
+		case oldBlock != newBlock:
+			changes = append(changes, fmt.Sprintf("~ %s: %d -> %d (%+d)", name, oldBlock, newBlock, newBlock-oldBlock))
+		}
+	}
+
+	return changes
+}
+
+// changeColors are the ANSI colors of added, removed and changed networks in a diff.
+var changeColors = map[byte]string{
+	'+': "\033[32m",
+	'-': "\033[31m",
+	'~': "\033[33m",
+}
+
+// printChanges prints the changes made by diffStartBlocks, colored when stdout is a terminal and NO_COLOR is unset.
+func printChanges(changes []string) {
+	color := os.Getenv("NO_COLOR") == ""
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		color = false
+	}
+
+	for _, change := range changes {
+		if code, ok := changeColors[change[0]]; ok && color {
+			change = code + change + "\033[0m"
+		}
+
+		fmt.Println(change)
+	}
+}
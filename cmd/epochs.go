@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var epochsCmd = &cobra.Command{
+	Use:   "epochs",
+	Short: "Find the start block of every network for several target timestamps, e.g. one per epoch",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timestampFlags, _ := cmd.Flags().GetStringSlice("timestamps")
+		timestampsPath, _ := cmd.Flags().GetString("timestamps-file")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		if timestampsPath != "" {
+			fileTimestamps, err := readTimestamps(timestampsPath)
+			if err != nil {
+				return err
+			}
+
+			timestampFlags = append(timestampFlags, fileTimestamps...)
+		}
+
+		if len(timestampFlags) == 0 {
+			return fmt.Errorf("no target timestamps given, use --timestamps or --timestamps-file")
+		}
+
+		targetTimestamps := make([]int64, 0, len(timestampFlags))
+		for _, timestampFlag := range timestampFlags {
+			targetTimestamp, err := parseTimestamp(timestampFlag)
+			if err != nil {
+				return fmt.Errorf("error parsing target timestamp %q: %w", timestampFlag, err)
+			}
+
+			targetTimestamps = append(targetTimestamps, targetTimestamp)
+		}
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		options, err := resolveOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer options.Close()
+
+		networkList, err := selectedNetworks(cmd, config)
+		if err != nil {
+			return err
+		}
+
+		// The table is over the given targets, so networks don't override them
+		for index := range networkList {
+			networkList[index].Target = ""
+		}
+		options.FromGenesis = false
+		table := epochTable{Epochs: make([]epoch, 0, len(targetTimestamps))}
+
+		var failed int
+
+		// Epochs are resolved one after the other, so that later ones benefit from the blocks cached by earlier ones
+		for _, targetTimestamp := range targetTimestamps {
+			// The epochs resolved before an interruption are still printed and written
+			if cmd.Context().Err() != nil {
+				slog.Warn("Interrupted, skipping the remaining epochs", "resolved", len(table.Epochs), "total", len(targetTimestamps))
+				break
+			}
+
+			slog.Info("Resolving epoch", "target", targetTimestamp, "target_time", time.Unix(targetTimestamp, 0).UTC().Format(time.RFC3339))
+
+			current := epoch{Timestamp: targetTimestamp, NetworkStartBlock: make(map[string]int64)}
+
+			for _, result := range resolveAll(cmd.Context(), networkList, targetTimestamp, options) {
+				// Networks without endpoints are left out of every epoch, so they are only reported for the first one
+				if result.notConfigured() {
+					if len(table.Epochs) == 0 {
+						slog.Warn("Network not configured, leaving it out", "network", result.Network.Name, "reason", result.Err)
+					}
+
+					continue
+				}
+
+				if result.Err != nil {
+					slog.Error("Error resolving network", "network", result.Network.Name, "target", targetTimestamp, "error", result.Err)
+					failed++
+					continue
+				}
+
+				current.NetworkStartBlock[result.Network.Name] = result.Block
+			}
+
+			table.Epochs = append(table.Epochs, current)
+		}
+
+		printEpochTable(networkList, table)
+
+		if outputPath != "" {
+			content, err := json.MarshalIndent(table, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error marshaling epoch table: %w", err)
+			}
+
+			if err := writeFile(outputPath, content); err != nil {
+				return err
+			}
+
+			slog.Info("Epoch table written", "path", outputPath)
+		}
+
+		if cmd.Context().Err() != nil {
+			return fmt.Errorf("interrupted after %d of %d epochs", len(table.Epochs), len(targetTimestamps))
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to resolve %d network epochs", failed)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	epochsCmd.Flags().StringSlice("timestamps", nil, "comma-separated target times as Unix seconds, RFC3339 dates or times before now (e.g. now-30d)")
+	epochsCmd.Flags().String("timestamps-file", "", "path to a file listing one target time per line, with # starting comments")
+	epochsCmd.Flags().String("output", "", "path to write the epoch table to as JSON")
+	addResolveFlags(epochsCmd)
+	addSelectFlags(epochsCmd)
+
+	rootCmd.AddCommand(epochsCmd)
+}
+
+// epochTable maps every target timestamp to the start block of every network.
+type epochTable struct {
+	Epochs []epoch `json:"epochs"`
+}
+
+// epoch holds the start blocks of the networks for a single target timestamp.
+type epoch struct {
+	Timestamp         int64            `json:"timestamp"`
+	NetworkStartBlock map[string]int64 `json:"network_start_block"`
+}
+
+// readTimestamps reads the target timestamps listed in the file at path, skipping blank lines and comments.
+func readTimestamps(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading timestamps file: %w", err)
+	}
+	defer file.Close()
+
+	var timestamps []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			timestamps = append(timestamps, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading timestamps file: %w", err)
+	}
+
+	return timestamps, nil
+}
+
+// printEpochTable prints table with a row per network and a column per epoch, leaving unresolved cells empty.
+func printEpochTable(networkList []Network, table epochTable) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := []string{"NETWORK"}
+	for _, epoch := range table.Epochs {
+		header = append(header, time.Unix(epoch.Timestamp, 0).UTC().Format(time.RFC3339))
+	}
+
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+
+	for _, network := range networkList {
+		row := []string{network.Name}
+
+		for _, epoch := range table.Epochs {
+			block, ok := epoch.NetworkStartBlock[network.Name]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+
+			row = append(row, strconv.FormatInt(block, 10))
+		}
+
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// EstimateReference is a known block of a network, from which blocks at other times are estimated with its average
+// block time when its endpoints can't be reached.
+type EstimateReference struct {
+	Block     int64 `json:"block"`
+	Timestamp int64 `json:"timestamp"`
+	// BlockTime is the average number of seconds between blocks since the reference block.
+	BlockTime float64 `json:"block_time"`
+}
+
+// estimateReferences are the built-in reference blocks of the networks of the profiles. The block times are averages,
+// so estimates drift the further the target is from the reference block.
+var estimateReferences = map[string]EstimateReference{
+	// The Merge, after which blocks come every 12 seconds
+	"ethereum": {Block: 15537394, Timestamp: 1663224179, BlockTime: 12},
+	// The Bedrock upgrade, after which blocks come every 2 seconds
+	"optimism": {Block: 105235063, Timestamp: 1686068903, BlockTime: 2},
+	// The Nitro genesis block. Blocks are produced on demand, about 4 a second
+	"arbitrum": {Block: 22207817, Timestamp: 1661956342, BlockTime: 0.25},
+	// The genesis block, after which blocks come every 2 seconds
+	"base": {Block: 0, Timestamp: 1686789347, BlockTime: 2},
+	// The fourth halving
+	"bitcoin": {Block: 840000, Timestamp: 1713571767, BlockTime: 600},
+}
+
+// estimateNetwork estimates the block of network at targetTimestamp from its reference block without any request,
+// from the estimate of its config or else the built-in one.
+func estimateNetwork(network Network, targetTimestamp int64) (result resolution) {
+	result.Network, result.Target, result.Estimated = network, targetTimestamp, true
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	if network.Target == genesisTarget {
+		result.Err = fmt.Errorf("the genesis of a network can't be estimated offline")
+		return result
+	}
+
+	if network.Target == deploymentTarget {
+		result.Err = fmt.Errorf("the deployment of contracts can't be estimated offline")
+		return result
+	}
+
+	if network.Target != "" {
+		target, err := parseTimestamp(network.Target)
+		if err != nil {
+			result.Err = fmt.Errorf("error parsing target of network: %w", err)
+			return result
+		}
+
+		result.Target = target
+	}
+
+	reference := network.Estimate
+	if reference == nil {
+		builtin, ok := estimateReferences[network.Name]
+		if !ok {
+			result.Err = fmt.Errorf("no reference block to estimate from, set the estimate of the network in the config")
+			return result
+		}
+
+		reference = &builtin
+	}
+
+	blocks := int64(math.Round(float64(result.Target-reference.Timestamp) / reference.BlockTime))
+	result.Block = max(reference.Block+blocks, network.MinBlock, 0)
+	result.BlockTimestamp = reference.Timestamp + int64(math.Round(float64(result.Block-reference.Block)*reference.BlockTime))
+
+	return result
+}
+
+// estimateAll estimates the block of every network at targetTimestamp. The results are in the same order as networks.
+func estimateAll(networks []Network, targetTimestamp int64) []resolution {
+	results := make([]resolution, 0, len(networks))
+	for _, network := range networks {
+		results = append(results, estimateNetwork(network, targetTimestamp))
+	}
+
+	return results
+}
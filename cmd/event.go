@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/blockfinder"
+)
+
+// eventSignaturePattern matches the signatures of events, like "Transfer(address,address,uint256)".
+var eventSignaturePattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*\([A-Za-z0-9_$,\[\]() ]*\)$`)
+
+var firstEventCmd = &cobra.Command{
+	Use:   "first-event",
+	Short: "Print the first block with a log of a contract",
+	Long: `Print the first block with a log emitted by a contract, with its timestamp as Unix seconds and as an RFC3339 date.
+Topics are matched in order, as a topic hash or an event signature like "Transfer(address,address,uint256)",
+and an empty topic matches any. The blocks after the deployment of the contract are scanned with eth_getLogs,
+narrowing the ranges the endpoint refuses as too wide.
+Set a network's anchor_event for its start blocks to never be before that block.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("network")
+		address, _ := cmd.Flags().GetString("address")
+		events, _ := cmd.Flags().GetStringArray("topic")
+
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid contract address %q", address)
+		}
+
+		topics := make([]string, len(events))
+		for index, event := range events {
+			if event != "" && !validEvent(event) {
+				return fmt.Errorf("invalid topic %q", event)
+			}
+
+			topics[index] = eventTopic(event)
+		}
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		network, err := lookupNetwork(config, name)
+		if err != nil {
+			return err
+		}
+
+		source, conn, err := dialNetwork(cmd.Context(), network)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if err := verifyChainID(cmd.Context(), network, source); err != nil {
+			return err
+		}
+
+		logFinder, ok := source.(blockfinder.LogFinder)
+		if !ok {
+			return fmt.Errorf("network type %q has no event logs", network.Type)
+		}
+
+		first, err := logFinder.FirstLogBlock(cmd.Context(), address, topics)
+		if err != nil {
+			return fmt.Errorf("error finding the first log of %s: %w", address, err)
+		}
+
+		fmt.Printf("%d\t%d\t%s\n", first.Number, first.Timestamp, time.Unix(first.Timestamp, 0).UTC().Format(time.RFC3339))
+
+		return nil
+	},
+}
+
+func init() {
+	firstEventCmd.Flags().String("network", "", "name of the network, as in the config or the networks of the profile")
+	firstEventCmd.Flags().String("address", "", "address of the contract")
+	firstEventCmd.Flags().StringArray("topic", nil, "topic hash or event signature the logs must have at this position, repeatable")
+	_ = firstEventCmd.MarkFlagRequired("network")
+	_ = firstEventCmd.MarkFlagRequired("address")
+
+	rootCmd.AddCommand(firstEventCmd)
+}
+
+// validEvent reports whether event is a 32-byte topic hash or an event signature.
+func validEvent(event string) bool {
+	if strings.HasPrefix(event, "0x") {
+		topic, err := hexutil.Decode(event)
+		return err == nil && len(topic) == common.HashLength
+	}
+
+	return eventSignaturePattern.MatchString(event)
+}
+
+// eventTopic returns the topic of event, hashing it if it is an event signature rather than a topic hash.
+func eventTopic(event string) string {
+	if event == "" || strings.HasPrefix(event, "0x") {
+		return event
+	}
+
+	return crypto.Keccak256Hash([]byte(strings.ReplaceAll(event, " ", ""))).Hex()
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// backupSuffix separates the name of a backed up file from the time of its backup.
+	backupSuffix = ".bak-"
+	// backupTimeLayout sorts backups chronologically by name, with nanoseconds so that backups taken within the
+	// same second don't overwrite each other.
+	backupTimeLayout = "20060102T150405.000000000Z"
+	// defaultFileMode is the mode of files written where there was none before.
+	defaultFileMode os.FileMode = 0o644
+	// keptBackups is the number of backups kept per file, older ones being removed.
+	keptBackups = 10
+)
+
+// writeFile writes content to path atomically: a reader, or a crash, sees either the old or the new content.
+// The file keeps the mode of the file it replaces, if any.
+func writeFile(path string, content []byte) error {
+	mode := defaultFileMode
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return writeFileMode(path, content, mode)
+}
+
+// writeFileMode writes content to path atomically, like writeFile, with the permissions mode.
+func writeFileMode(path string, content []byte, mode os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	// Removing fails harmlessly once the file was renamed
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("error syncing %s: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error replacing %s: %w", path, err)
+	}
+
+	// Persist the rename itself, where the platform allows syncing directories
+	if directory, err := os.Open(filepath.Dir(path)); err == nil {
+		_ = directory.Sync()
+		directory.Close()
+	}
+
+	return nil
+}
+
+// replaceFile backs up the file at path, if there is one, and then atomically replaces it with content.
+func replaceFile(path string, content []byte) error {
+	previous, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading %s for backup: %w", path, err)
+	}
+
+	if err == nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s for backup: %w", path, err)
+		}
+
+		// Backups can hold the same secrets as the file, so they keep its mode
+		backupPath := newBackupPath(path, time.Now())
+		if err := writeFileMode(backupPath, previous, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("error backing up %s: %w", path, err)
+		}
+
+		slog.Debug("Backed up file", "path", path, "backup", backupPath)
+
+		pruneBackups(path)
+	}
+
+	return writeFile(path, content)
+}
+
+// newBackupPath returns the path of a new backup of the file at path taken at now. A counter is added in the
+// unlikely case that a backup of the same time already exists, on platforms with coarse clocks.
+func newBackupPath(path string, now time.Time) string {
+	backupPath := path + backupSuffix + now.UTC().Format(backupTimeLayout)
+
+	candidate := backupPath
+	for count := 1; ; count++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+
+		candidate = fmt.Sprintf("%s-%d", backupPath, count)
+	}
+}
+
+// backups returns the paths of the backups of the file at path, oldest first.
+func backups(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + backupSuffix + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	// The backup time layout sorts by name
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
+// pruneBackups removes all but the most recent keptBackups backups of the file at path.
+func pruneBackups(path string) {
+	paths, err := backups(path)
+	if err != nil {
+		slog.Warn("Error listing backups", "path", path, "error", err)
+		return
+	}
+
+	for _, backupPath := range paths[:max(len(paths)-keptBackups, 0)] {
+		if err := os.Remove(backupPath); err != nil {
+			slog.Warn("Error removing old backup", "backup", backupPath, "error", err)
+		}
+	}
+}
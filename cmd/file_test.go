@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReplaceFileKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	if err := replaceFile(path, []byte("{}")); err != nil {
+		t.Fatalf("replaceFile() error = %v", err)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != defaultFileMode {
+		t.Errorf("new file has mode %v, want %v", info.Mode().Perm(), defaultFileMode)
+	}
+
+	// Configs holding secrets are often only readable by their owner
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(path, []byte(`{"version": 2}`)); err != nil {
+		t.Fatalf("replaceFile() error = %v", err)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("replaced file has mode %v, want 0600", info.Mode().Perm())
+	}
+
+	paths, err := backups(path)
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("backups() = %v, %v, want a single backup", paths, err)
+	}
+
+	if info, _ := os.Stat(paths[0]); info.Mode().Perm() != 0o600 {
+		t.Errorf("backup has mode %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestReplaceFileBackupsWithinASecond(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	contents := []string{"first", "second", "third", "fourth"}
+	for _, content := range contents {
+		if err := replaceFile(path, []byte(content)); err != nil {
+			t.Fatalf("replaceFile() error = %v", err)
+		}
+	}
+
+	paths, err := backups(path)
+	if err != nil {
+		t.Fatalf("backups() error = %v", err)
+	}
+
+	// Every content but the current one is backed up, oldest first
+	if len(paths) != len(contents)-1 {
+		t.Fatalf("backups() = %v, want %d backups", paths, len(contents)-1)
+	}
+
+	for index, backupPath := range paths {
+		content, err := os.ReadFile(backupPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != contents[index] {
+			t.Errorf("backup %d holds %q, want %q", index, content, contents[index])
+		}
+	}
+}
+
+func TestNewBackupPathCollision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	want := []string{
+		path + ".bak-20240701T120000.000000000Z",
+		path + ".bak-20240701T120000.000000000Z-1",
+		path + ".bak-20240701T120000.000000000Z-2",
+	}
+
+	// Backups taken at the same time get a counter rather than the name of the existing one
+	for _, wantPath := range want {
+		backupPath := newBackupPath(path, now)
+		if backupPath != wantPath {
+			t.Fatalf("newBackupPath() = %s, want %s", backupPath, wantPath)
+		}
+
+		if err := os.WriteFile(backupPath, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := backups(path)
+	if err != nil {
+		t.Fatalf("backups() error = %v", err)
+	}
+
+	if !slices.Equal(paths, want) {
+		t.Errorf("backups() = %v, want %v", paths, want)
+	}
+}
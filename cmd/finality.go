@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/provider"
+)
+
+// finalityMode selects what happens to start blocks that aren't final yet, and so may still be reorganized.
+type finalityMode string
+
+const (
+	// finalityRequire fails networks whose start block isn't final.
+	finalityRequire finalityMode = "require"
+	// finalityWarn keeps start blocks that aren't final, logging a warning.
+	finalityWarn finalityMode = "warn"
+	// finalityOff skips the finality check.
+	finalityOff finalityMode = "off"
+)
+
+// parseFinalityMode parses a finality mode name.
+func parseFinalityMode(value string) (finalityMode, error) {
+	switch mode := finalityMode(value); mode {
+	case finalityRequire, finalityWarn, finalityOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid finality mode %q: must be require, warn or off", value)
+	}
+}
+
+// checkFinality checks that block of network is final according to finder, failing or warning depending on mode.
+// Finders that can't tell which blocks are final, and failures to ask them, skip the check.
+func checkFinality(ctx context.Context, network Network, finder provider.Source, block blockfinder.BlockRef, mode finalityMode) error {
+	finalizer, ok := finder.(blockfinder.Finalizer)
+	if !ok || mode == finalityOff {
+		return nil
+	}
+
+	finalized, err := finalizer.FinalizedHeight(ctx)
+	if errors.Is(err, blockfinder.ErrNoFinality) {
+		return nil
+	}
+
+	if err != nil {
+		slog.Warn("Error getting the finalized block, skipping the finality check", "network", network.Name, "error", err)
+		return nil
+	}
+
+	if block.Number <= finalized {
+		return nil
+	}
+
+	if mode == finalityWarn {
+		slog.Warn("Start block is not final yet and may still be reorganized", "network", network.Name, "block", block.Number, "finalized", finalized)
+		return nil
+	}
+
+	return fmt.Errorf("block %d is not final yet, the latest finalized block is %d", block.Number, finalized)
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputOptions controls how the start blocks of a config are written.
+type outputOptions struct {
+	// Compat writes JSON configs in the flat layout of version 1.
+	Compat bool
+	// ConfigMapName and ConfigMapNamespace name the ConfigMap written by the k8s-configmap format.
+	ConfigMapName      string
+	ConfigMapNamespace string
+	// HelmKey is the dot-separated path of the values the helm-values format nests the parameters under.
+	HelmKey string
+}
+
+// configWriters write the start blocks of a config to a file, by output format.
+var configWriters = map[string]func(path string, config *Config, options outputOptions) error{
+	"json": func(path string, config *Config, options outputOptions) error {
+		return writeConfig(path, config, options.Compat)
+	},
+	"yaml": func(path string, config *Config, options outputOptions) error {
+		return writeYAMLConfig(path, config, options.Compat)
+	},
+	"toml": func(path string, config *Config, _ outputOptions) error {
+		return writeTOMLConfig(path, config)
+	},
+	"env": func(path string, config *Config, _ outputOptions) error {
+		return writeEnvConfig(path, config)
+	},
+	"k8s-configmap": writeConfigMap,
+	"helm-values":   writeHelmValues,
+	"tfvars": func(path string, config *Config, _ outputOptions) error {
+		return writeTFVarsConfig(path, config)
+	},
+}
+
+// standaloneFormats are the output formats written as files of their own rather than into the config, which
+// --output must name.
+var standaloneFormats = map[string]bool{"toml": true, "env": true, "k8s-configmap": true, "helm-values": true, "tfvars": true, "csv": true, "md": true}
+
+// contentTypes are the media types of the output formats, which uploads are labeled with.
+var contentTypes = map[string]string{
+	"json":          "application/json",
+	"yaml":          "application/yaml",
+	"toml":          "application/toml",
+	"env":           "text/plain; charset=utf-8",
+	"k8s-configmap": "application/yaml",
+	"helm-values":   "application/yaml",
+	"tfvars":        "text/plain; charset=utf-8",
+	"csv":           "text/csv; charset=utf-8",
+	"md":            "text/markdown; charset=utf-8",
+}
+
+// contentType returns the media type of the output written at path in format.
+func contentType(format, path string) string {
+	// tfvars outputs are written in the JSON syntax to paths ending in .json
+	if format == "tfvars" && strings.HasSuffix(path, ".json") {
+		return contentTypes["json"]
+	}
+
+	if value, ok := contentTypes[format]; ok {
+		return value
+	}
+
+	return "application/octet-stream"
+}
+
+// Prefixes of the variables of env outputs, followed by the network name in upper case with dashes as underscores.
+const (
+	envStartBlockPrefix         = "NODE_START_BLOCK_"
+	envBlockTimePrefix          = "NODE_BLOCK_TIME_"
+	envGenesisTimestampPrefix   = "NODE_GENESIS_TIMESTAMP_"
+	envFarcasterTimestampPrefix = "NODE_FARCASTER_TIMESTAMP_"
+)
+
+// configSection is a section of the node parameters of a config, mapping every network to a value.
+type configSection struct {
+	Name      string
+	EnvPrefix string
+	Values    map[string]string
+}
+
+// configSections returns the node parameters of config, in the layout of version 1, skipping empty sections.
+func configSections(config *Config) []configSection {
+	sections := []configSection{
+		{Name: "network_start_block", EnvPrefix: envStartBlockPrefix, Values: formatValues(config.StartBlockNumbers())},
+		{Name: "network_block_time", EnvPrefix: envBlockTimePrefix, Values: formatFloats(config.NetworkBlockTime)},
+		{Name: "network_genesis_timestamp", EnvPrefix: envGenesisTimestampPrefix, Values: formatValues(config.NetworkGenesisTimestamp)},
+		{Name: "network_farcaster_timestamp", EnvPrefix: envFarcasterTimestampPrefix, Values: formatValues(config.NetworkFarcasterTimestamp)},
+	}
+
+	// Start blocks are always written, even when there are none yet
+	nonEmpty := sections[:1]
+	for _, section := range sections[1:] {
+		if len(section.Values) > 0 {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+
+	return nonEmpty
+}
+
+// formatValues formats the integer values of a section.
+func formatValues(values map[string]int64) map[string]string {
+	formatted := make(map[string]string, len(values))
+	for network, value := range values {
+		formatted[network] = strconv.FormatInt(value, 10)
+	}
+
+	return formatted
+}
+
+// formatFloats formats the decimal values of a section.
+func formatFloats(values map[string]float64) map[string]string {
+	formatted := make(map[string]string, len(values))
+	for network, value := range values {
+		formatted[network] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return formatted
+}
+
+// sortedNetworks returns the networks of values in order, so that outputs don't change between runs.
+func sortedNetworks(values map[string]string) []string {
+	networks := make([]string, 0, len(values))
+	for network := range values {
+		networks = append(networks, network)
+	}
+
+	sort.Strings(networks)
+
+	return networks
+}
+
+// writeTOMLConfig writes the node parameters of config to path as TOML, with a table per section. The file is
+// written whole, as it only holds the parameters.
+func writeTOMLConfig(path string, config *Config) error {
+	var builder strings.Builder
+
+	for index, section := range configSections(config) {
+		if index > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "[%s]\n", section.Name)
+
+		for _, network := range sortedNetworks(section.Values) {
+			fmt.Fprintf(&builder, "%s = %s\n", tomlKey(network), section.Values[network])
+		}
+	}
+
+	return replaceFile(path, []byte(builder.String()))
+}
+
+// tomlKey returns network as a TOML key, quoted unless it is a valid bare key.
+func tomlKey(network string) string {
+	for _, r := range network {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return strconv.Quote(network)
+		}
+	}
+
+	return network
+}
+
+// writeEnvConfig writes the node parameters of config to path as an environment file, like
+// NODE_START_BLOCK_ETHEREUM=20000000, which systemd units and docker-compose read without a JSON parser.
+func writeEnvConfig(path string, config *Config) error {
+	var builder strings.Builder
+
+	for _, section := range configSections(config) {
+		fmt.Fprintf(&builder, "# %s\n", section.Name)
+
+		for _, network := range sortedNetworks(section.Values) {
+			fmt.Fprintf(&builder, "%s%s=%s\n", section.EnvPrefix, envName(network), section.Values[network])
+		}
+	}
+
+	return replaceFile(path, []byte(builder.String()))
+}
+
+// writeTFVarsConfig writes the node parameters of config to path as Terraform variable definitions, with a map
+// variable per section, like network_start_block = { ethereum = 20000000 }. Paths ending in .json, like
+// node.auto.tfvars.json, are written in the JSON syntax of tfvars instead.
+func writeTFVarsConfig(path string, config *Config) error {
+	if strings.HasSuffix(path, ".json") {
+		content, err := json.MarshalIndent(nodeParams(config), "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling config: %w", err)
+		}
+
+		return replaceFile(path, append(content, '\n'))
+	}
+
+	var builder strings.Builder
+
+	for index, section := range configSections(config) {
+		if index > 0 {
+			builder.WriteString("\n")
+		}
+
+		networks := sortedNetworks(section.Values)
+
+		// Align the values like terraform fmt does
+		width := 0
+		for _, network := range networks {
+			width = max(width, len(tfvarsKey(network)))
+		}
+
+		fmt.Fprintf(&builder, "%s = {\n", section.Name)
+
+		for _, network := range networks {
+			fmt.Fprintf(&builder, "  %-*s = %s\n", width, tfvarsKey(network), section.Values[network])
+		}
+
+		builder.WriteString("}\n")
+	}
+
+	return replaceFile(path, []byte(builder.String()))
+}
+
+// tfvarsKey returns network as a key of an HCL object, quoted unless it is a valid identifier.
+func tfvarsKey(network string) string {
+	for i, r := range network {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && (r >= '0' && r <= '9' || r == '-')) {
+			return strconv.Quote(network)
+		}
+	}
+
+	if network == "" {
+		return `""`
+	}
+
+	return network
+}
+
+// configMap is a Kubernetes ConfigMap.
+type configMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   configMapMetadata `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type configMapMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// configMapParamsKey is the key of the ConfigMap holding the node parameters as a YAML file, for mounting.
+const configMapParamsKey = "network_params.yaml"
+
+// writeConfigMap writes the node parameters of config to path as a Kubernetes ConfigMap, for CD pipelines to apply
+// directly. It holds every parameter as the environment variable of the env format, for envFrom, along with the
+// parameters as a YAML file under network_params.yaml, for mounting next to the node config.
+func writeConfigMap(path string, config *Config, options outputOptions) error {
+	params, err := marshalYAML(nodeParams(config))
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{configMapParamsKey: string(params)}
+	for _, section := range configSections(config) {
+		for network, value := range section.Values {
+			data[section.EnvPrefix+envName(network)] = value
+		}
+	}
+
+	content, err := marshalYAML(configMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: configMapMetadata{
+			Name:      options.ConfigMapName,
+			Namespace: options.ConfigMapNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "get-node-start-block"},
+		},
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(path, content)
+}
+
+// writeHelmValues writes the node parameters of config to path as a Helm values file, nested under the
+// dot-separated path options.HelmKey, for the chart to render into the node config.
+func writeHelmValues(path string, config *Config, options outputOptions) error {
+	var values interface{} = nodeParams(config)
+
+	keys := strings.Split(options.HelmKey, ".")
+	for i := len(keys) - 1; i >= 0; i-- {
+		values = map[string]interface{}{keys[i]: values}
+	}
+
+	content, err := marshalYAML(values)
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(path, content)
+}
+
+// nodeParams returns the node parameters of config as YAML values, by section, with numbers rather than strings.
+func nodeParams(config *Config) map[string]interface{} {
+	params := map[string]interface{}{"network_start_block": config.StartBlockNumbers()}
+
+	if len(config.NetworkBlockTime) > 0 {
+		params["network_block_time"] = config.NetworkBlockTime
+	}
+
+	if len(config.NetworkGenesisTimestamp) > 0 {
+		params["network_genesis_timestamp"] = config.NetworkGenesisTimestamp
+	}
+
+	if len(config.NetworkFarcasterTimestamp) > 0 {
+		params["network_farcaster_timestamp"] = config.NetworkFarcasterTimestamp
+	}
+
+	return params
+}
+
+// marshalYAML encodes value as YAML, indented like the RSS3 Node example config.
+func marshalYAML(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// envName returns network as part of an environment variable name: in upper case, with every character other than
+// letters and digits replaced by an underscore.
+func envName(network string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, network)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"get-node-start-block/pkg/github"
+)
+
+const (
+	// githubTokenEnv is the environment variable holding the GitHub token used by --github-pr.
+	githubTokenEnv = "GITHUB_TOKEN"
+	// githubAPIURLEnv is the environment variable holding the URL of the GitHub API, for GitHub Enterprise Server.
+	// GitHub Actions sets it on every run.
+	githubAPIURLEnv = "GITHUB_API_URL"
+	// branchTimeLayout names the branches of proposals after the time they were opened.
+	branchTimeLayout = "20060102T150405Z"
+)
+
+// githubOptions describes where --github-pr proposes the written config.
+type githubOptions struct {
+	Repository github.Repository
+	// Base is the branch the pull request targets, the default branch of the repository if empty.
+	Base string
+	// Path is the path of the config in the repository.
+	Path  string
+	Token string
+	// APIURL is the URL of the GitHub API, the public API if empty.
+	APIURL string
+}
+
+// proposeConfig commits the config written at localPath to a new branch of the repository of options
+// and opens a pull request for it, with runReport in its description.
+func proposeConfig(ctx context.Context, options githubOptions, localPath string, runReport *report) error {
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", localPath, err)
+	}
+
+	path := options.Path
+	if path == "" {
+		path = repositoryPath(localPath)
+	}
+
+	targetTime := time.Unix(runReport.Target, 0).UTC()
+
+	change := github.Change{
+		Base:    options.Base,
+		Branch:  fmt.Sprintf("start-blocks/%d-%s", runReport.Target, time.Now().UTC().Format(branchTimeLayout)),
+		Path:    path,
+		Content: content,
+		Message: fmt.Sprintf("Update start blocks for %s", targetTime.Format(time.RFC3339)),
+		Body:    markdownReport(runReport),
+	}
+
+	pullRequest, err := github.NewClient(options.APIURL, options.Token).ProposeChange(ctx, options.Repository, change)
+	if err != nil {
+		return fmt.Errorf("error proposing config on %s: %w", options.Repository, err)
+	}
+
+	if pullRequest == nil {
+		slog.Info("Config already up to date on GitHub, no pull request opened", "repository", options.Repository.String(), "path", path)
+		return nil
+	}
+
+	slog.Info("Pull request opened", "repository", options.Repository.String(), "number", pullRequest.Number, "url", pullRequest.HTMLURL)
+
+	return nil
+}
+
+// repositoryPath returns the path in the repository of the config written at localPath:
+// relative paths are kept, while absolute ones are reduced to the file name.
+func repositoryPath(localPath string) string {
+	if filepath.IsAbs(localPath) {
+		return filepath.Base(localPath)
+	}
+
+	return filepath.ToSlash(filepath.Clean(localPath))
+}
+
+// markdownReport renders runReport as a Markdown pull request description.
+func markdownReport(runReport *report) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Start blocks resolved for `%d` (%s), picking the block %s the target.\n\n",
+		runReport.Target, time.Unix(runReport.Target, 0).UTC().Format(time.RFC3339), runReport.Direction)
+	fmt.Fprintf(&builder, "%d networks resolved, %d failed.\n\n", runReport.Succeeded, runReport.Failed)
+
+	builder.WriteString("| Network | Block | Difference | RPC calls | Error |\n")
+	builder.WriteString("| --- | ---: | ---: | ---: | --- |\n")
+
+	for _, network := range runReport.Networks {
+		block, difference := "", ""
+		if network.Block != nil {
+			block = fmt.Sprint(*network.Block)
+			difference = (time.Duration(*network.Difference) * time.Second).String()
+		}
+
+		fmt.Fprintf(&builder, "| %s | %s | %s | %d | %s |\n",
+			network.Network, block, difference, network.RPCCalls, strings.ReplaceAll(network.Error, "|", `\|`))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/provider"
+)
+
+// changeGuard refuses start blocks that moved further in time from the previous start block of their network
+// than allowed, as a mistyped target would make nodes index again from a very different block.
+type changeGuard struct {
+	// Previous is the start block of every network before the run.
+	Previous map[string]int64
+	// MaxChange is the largest time allowed between the previous and the new start block.
+	MaxChange time.Duration
+}
+
+// check returns an error if block moved too far from the previous start block of network.
+// Networks without a previous start block may start anywhere.
+func (g *changeGuard) check(ctx context.Context, network Network, finder provider.Source, block blockfinder.BlockRef) error {
+	if g == nil {
+		return nil
+	}
+
+	previous, ok := g.Previous[network.Name]
+	if !ok || previous == block.Number {
+		return nil
+	}
+
+	previousTimestamp, err := finder.BlockTimestamp(ctx, previous)
+	if err != nil {
+		return fmt.Errorf("error getting timestamp of previous start block %d: %v", previous, err)
+	}
+
+	change := time.Duration(abs(block.Timestamp-previousTimestamp)) * time.Second
+	if change <= g.MaxChange {
+		return nil
+	}
+
+	direction := "forwards"
+	if block.Number < previous {
+		direction = "backwards"
+	}
+
+	return fmt.Errorf("start block would move %s by %s from block %d to %d, beyond the maximum change of %s (use --force to allow it)",
+		direction, change, previous, block.Number, g.MaxChange)
+}
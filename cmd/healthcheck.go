@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+	"get-node-start-block/pkg/provider"
+	"get-node-start-block/pkg/retry"
+)
+
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "Check that the endpoints of every network are reachable",
+	Long: `Dial every endpoint of every network and report its chain ID, latest block, latency, whether it serves
+old blocks and the rate limit headers of its responses. The command fails if a configured network has no reachable
+endpoint, which makes it usable before a run and as a Kubernetes readiness probe. Networks without endpoints are left
+out, like resolve does, unless listed with --require.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		networkList, err := selectedNetworks(cmd, config)
+		if err != nil {
+			return err
+		}
+
+		checks := make([][]endpointCheck, len(networkList))
+
+		var wg sync.WaitGroup
+		for i, network := range networkList {
+			wg.Add(1)
+
+			go func(i int, network Network) {
+				defer wg.Done()
+
+				checks[i] = checkNetwork(cmd.Context(), network, timeout)
+			}(i, network)
+		}
+
+		wg.Wait()
+
+		printHealthTable(networkList, checks)
+
+		var unreachable []string
+
+		for i, network := range networkList {
+			reachable := false
+			for _, check := range checks[i] {
+				reachable = reachable || check.Err == nil
+			}
+
+			if reachable {
+				continue
+			}
+
+			if len(network.URLs) == 0 && !network.Required {
+				slog.Warn("Network not configured, leaving it out", "network", network.Name, "reason", checks[i][0].Err)
+				continue
+			}
+
+			unreachable = append(unreachable, network.Name)
+		}
+
+		if len(unreachable) > 0 {
+			return fmt.Errorf("%d networks unreachable: %s", len(unreachable), strings.Join(unreachable, ", "))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	healthcheckCmd.Flags().Duration("timeout", 10*time.Second, "maximum time spent checking a single endpoint")
+	addSelectFlags(healthcheckCmd)
+
+	rootCmd.AddCommand(healthcheckCmd)
+}
+
+// rateLimitHeaders are the response headers endpoints report their rate limits in, either the common X-RateLimit-*
+// headers or the RateLimit-* headers of the IETF draft, along with Retry-After once limited.
+var rateLimitHeaders = []string{
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+	"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+	"Retry-After",
+}
+
+// endpointCheck is the outcome of checking an endpoint of a network.
+type endpointCheck struct {
+	URL     string
+	ChainID int64
+	Head    int64
+	Latency time.Duration
+	// Archive is "yes" or "no" for endpoints that were checked for old blocks, and empty for the rest.
+	Archive string
+	// RateLimit are the rate limit headers of the last response of the endpoint, as key=value pairs.
+	RateLimit []string
+	Err       error
+}
+
+// checkNetwork checks every endpoint of network on its own, in the order they are configured.
+func checkNetwork(ctx context.Context, network Network, timeout time.Duration) []endpointCheck {
+	if len(network.URLs) == 0 {
+		return []endpointCheck{{URL: "-", Err: notConfiguredError(network)}}
+	}
+
+	checks := make([]endpointCheck, 0, len(network.URLs))
+	for _, url := range network.URLs {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		checks = append(checks, checkEndpoint(ctx, network, url))
+		cancel()
+	}
+
+	return checks
+}
+
+// checkEndpoint dials network through url alone. Only failing to get the chain head or serving the wrong chain make
+// the endpoint unreachable, as endpoints serving recent blocks alone are enough for some runs.
+func checkEndpoint(ctx context.Context, network Network, url string) endpointCheck {
+	check := endpointCheck{URL: endpoint.Redact(url)}
+	logger := slog.With("network", network.Name, "endpoint", check.URL)
+
+	network.URLs = []string{url}
+
+	config, err := network.providerConfig()
+	if err != nil {
+		check.Err = err
+		return check
+	}
+
+	// Retries would hide flaky endpoints and skew their latency
+	config.Retry = retry.NoRetry
+
+	var (
+		locker  sync.Mutex
+		headers http.Header
+	)
+
+	config.ObserveResponse = func(response *http.Response) {
+		locker.Lock()
+		defer locker.Unlock()
+
+		headers = response.Header.Clone()
+	}
+
+	source, conn, err := provider.Dial(network.Type, config)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	defer conn.Close()
+
+	start := time.Now()
+
+	if check.Head, err = source.LatestHeight(ctx); err != nil {
+		check.Err = fmt.Errorf("error getting chain head: %w", err)
+		logger.Error("Endpoint unreachable", "error", check.Err)
+
+		return check
+	}
+
+	check.Latency = time.Since(start)
+
+	if identifier, ok := source.(blockfinder.ChainIdentifier); ok {
+		chainID, err := identifier.ChainID(ctx)
+		switch {
+		case err == nil:
+			check.ChainID = chainID
+		case !errors.Is(err, blockfinder.ErrNoChainID):
+			logger.Warn("Error getting chain ID", "error", err)
+		}
+	}
+
+	if network.ChainID != 0 && check.ChainID != 0 && check.ChainID != network.ChainID {
+		check.Err = fmt.Errorf("endpoint serves chain ID %d, expected %d: check the endpoint URL", check.ChainID, network.ChainID)
+		logger.Error("Endpoint serves the wrong chain", "error", check.Err)
+
+		return check
+	}
+
+	if checker, ok := source.(blockfinder.ArchiveChecker); ok {
+		check.Archive = "yes"
+		if err := checker.CheckArchive(ctx); err != nil {
+			check.Archive = "no"
+			logger.Warn("Endpoint doesn't serve old blocks", "error", err)
+		}
+	}
+
+	locker.Lock()
+	for _, key := range rateLimitHeaders {
+		if value := headers.Get(key); value != "" {
+			check.RateLimit = append(check.RateLimit, strings.ToLower(key)+"="+value)
+		}
+	}
+	locker.Unlock()
+
+	logger.Debug("Endpoint reachable", "head", check.Head, "latency", check.Latency)
+
+	return check
+}
+
+// printHealthTable prints a row per endpoint of every network in networkList, with checks in the same order.
+func printHealthTable(networkList []Network, checks [][]endpointCheck) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "NETWORK\tENDPOINT\tCHAIN ID\tLATEST BLOCK\tLATENCY\tARCHIVE\tRATE LIMIT\tSTATUS")
+
+	for i, network := range networkList {
+		for _, check := range checks[i] {
+			row := []string{network.Name, check.URL, "-", "-", "-", "-", "-", "ok"}
+
+			if check.ChainID != 0 {
+				row[2] = strconv.FormatInt(check.ChainID, 10)
+			}
+
+			if check.Err == nil {
+				row[3] = strconv.FormatInt(check.Head, 10)
+				row[4] = check.Latency.Round(time.Millisecond).String()
+			}
+
+			if check.Archive != "" {
+				row[5] = check.Archive
+			}
+
+			if len(check.RateLimit) > 0 {
+				row[6] = strings.Join(check.RateLimit, " ")
+			}
+
+			if check.Err != nil {
+				row[7] = check.Err.Error()
+			}
+
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+	}
+
+	writer.Flush()
+}
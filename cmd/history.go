@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+
+	"get-node-start-block/pkg/history"
+)
+
+// historyEnv holds the database --history records runs to when the flag is not given, since database URLs hold
+// passwords better kept out of command lines.
+const historyEnv = "HISTORY_DATABASE_URL"
+
+// recordHistory appends the run summarized by runReport, with the endpoints of results, to the history in db.
+func recordHistory(ctx context.Context, db *history.DB, runReport *report, results []resolution) error {
+	run := history.Run{
+		Target:      runReport.Target,
+		Direction:   runReport.Direction,
+		StartedAt:   runReport.StartedAt,
+		FinishedAt:  runReport.FinishedAt,
+		Resolutions: make([]history.Resolution, 0, len(results)),
+	}
+
+	for index, networkReport := range runReport.Networks {
+		resolution := history.Resolution{
+			Network:        networkReport.Network,
+			Target:         results[index].Target,
+			Block:          networkReport.Block,
+			BlockTimestamp: networkReport.BlockTimestamp,
+			Difference:     networkReport.Difference,
+			RPCCalls:       networkReport.RPCCalls,
+			DurationMS:     networkReport.DurationMS,
+			Error:          networkReport.Error,
+		}
+
+		for _, health := range results[index].Endpoints {
+			if health.Successes > 0 {
+				resolution.Endpoints = append(resolution.Endpoints, health.URL)
+			}
+		}
+
+		run.Resolutions = append(run.Resolutions, resolution)
+	}
+
+	return db.Record(ctx, run)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"get-node-start-block/pkg/kvstore"
+)
+
+// Keys the params are written to under the prefix of a --kv location.
+const (
+	// kvParamsKey holds the params of every network, in the sections of the node config.
+	kvParamsKey = "params"
+	// kvNetworksPrefix holds a key per network, with the params of the network alone.
+	kvNetworksPrefix = "networks/"
+)
+
+// kvDestination is a key-value store --kv writes the params to.
+type kvDestination struct {
+	// URL is the location as given to --kv.
+	URL      string
+	Location kvstore.Location
+	Store    kvstore.Store
+}
+
+// newKVDestination returns the destination of the location given to --kv, with credentials from the environment:
+// ETCD_USERNAME and ETCD_PASSWORD for etcd://, and CONSUL_HTTP_TOKEN for consul://.
+func newKVDestination(rawURL string) (*kvDestination, error) {
+	location, err := kvstore.ParseLocation(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := kvstore.Credentials{
+		Username: os.Getenv("ETCD_USERNAME"),
+		Password: os.Getenv("ETCD_PASSWORD"),
+		Token:    os.Getenv("CONSUL_HTTP_TOKEN"),
+	}
+
+	return &kvDestination{URL: rawURL, Location: location, Store: kvstore.New(location, credentials)}, nil
+}
+
+// writeKV writes the params of config to destination, a key per network under networks/ and all of them under
+// params. The combined key is written last, so that nodes watching it never read it ahead of the network keys, and
+// the keys of networks no longer in the config are deleted once it is written.
+func writeKV(ctx context.Context, destination *kvDestination, config *Config) error {
+	location := destination.Location
+	written := make(map[string]bool)
+
+	networks := networkParams(config)
+	for network, params := range networks {
+		key := location.Key(kvNetworksPrefix + network)
+
+		value, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("error marshaling params of %s: %w", network, err)
+		}
+
+		if err := destination.Store.Put(ctx, key, value); err != nil {
+			return err
+		}
+
+		written[key] = true
+	}
+
+	params, err := json.Marshal(nodeParams(config))
+	if err != nil {
+		return fmt.Errorf("error marshaling params: %w", err)
+	}
+
+	if err := destination.Store.Put(ctx, location.Key(kvParamsKey), params); err != nil {
+		return err
+	}
+
+	keys, err := destination.Store.Keys(ctx, location.Key(kvNetworksPrefix))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if written[key] {
+			continue
+		}
+
+		if err := destination.Store.Delete(ctx, key); err != nil {
+			return err
+		}
+
+		slog.Debug("Deleted params of removed network", "location", destination.URL, "key", key)
+	}
+
+	slog.Info("Params written to key-value store", "location", destination.URL, "networks", len(networks))
+
+	return nil
+}
+
+// networkParams returns the params of config by network, each in the sections of the node config.
+func networkParams(config *Config) map[string]map[string]interface{} {
+	networks := make(map[string]map[string]interface{})
+
+	set := func(network, section string, value interface{}) {
+		if networks[network] == nil {
+			networks[network] = make(map[string]interface{})
+		}
+
+		networks[network][section] = value
+	}
+
+	for network, block := range config.StartBlockNumbers() {
+		set(network, "network_start_block", block)
+	}
+
+	for network, blockTime := range config.NetworkBlockTime {
+		set(network, "network_block_time", blockTime)
+	}
+
+	for network, timestamp := range config.NetworkGenesisTimestamp {
+		set(network, "network_genesis_timestamp", timestamp)
+	}
+
+	for network, timestamp := range config.NetworkFarcasterTimestamp {
+		set(network, "network_farcaster_timestamp", timestamp)
+	}
+
+	return networks
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/provider"
+)
+
+// lookupBackend is an indexer service looking blocks up by timestamp, tried before searching the endpoints of a
+// network. Backends serving EVM chains by chain ID are used once their API key is set, the others once the network
+// configures them.
+type lookupBackend struct {
+	// APIKeyEnv is the environment variable holding the API key of the backend, if it needs one.
+	APIKeyEnv string
+	// URLEnv is the environment variable holding the URL of the API, overriding DefaultURL for compatible APIs.
+	URLEnv     string
+	DefaultURL string
+	// endpoint returns how the backend serves network from the API at apiURL with apiKey, with the URL and headers
+	// of its lookup, or false if it doesn't serve it.
+	endpoint func(network Network, apiURL, apiKey string) (Network, bool)
+}
+
+// lookupBackends are the block lookup backends by name, as selected by --lookups.
+var lookupBackends = map[string]lookupBackend{
+	// The multichain Etherscan API serves every chain it indexes by chain ID
+	"etherscan": {
+		APIKeyEnv:  "ETHERSCAN_API_KEY",
+		URLEnv:     "ETHERSCAN_API_URL",
+		DefaultURL: "https://api.etherscan.io/v2/api",
+		endpoint: func(network Network, apiURL, apiKey string) (Network, bool) {
+			query := url.Values{"chainid": {fmt.Sprint(network.ChainID)}, "apikey": {apiKey}}
+
+			return Network{URLs: []string{apiURL + "?" + query.Encode()}}, evmChain(network)
+		},
+	},
+	"moralis": {
+		APIKeyEnv:  "MORALIS_API_KEY",
+		URLEnv:     "MORALIS_API_URL",
+		DefaultURL: "https://deep-index.moralis.io/api/v2.2",
+		endpoint: func(network Network, apiURL, apiKey string) (Network, bool) {
+			return Network{
+				URLs:    []string{fmt.Sprintf("%s?chain=0x%x", apiURL, network.ChainID)},
+				Headers: map[string]string{"X-API-Key": apiKey},
+			}, evmChain(network)
+		},
+	},
+	"covalent": {
+		APIKeyEnv:  "COVALENT_API_KEY",
+		URLEnv:     "COVALENT_API_URL",
+		DefaultURL: "https://api.covalenthq.com/v1",
+		endpoint: func(network Network, apiURL, apiKey string) (Network, bool) {
+			query := url.Values{"key": {apiKey}}
+
+			return Network{URLs: []string{fmt.Sprintf("%s/%d?%s", strings.TrimSuffix(apiURL, "/"), network.ChainID, query.Encode())}}, evmChain(network)
+		},
+	},
+	// Blocks subgraphs are deployed per chain, so every network names its own
+	"subgraph": {
+		endpoint: func(network Network, _, _ string) (Network, bool) {
+			subgraph, ok := expandEndpoint(network.BlockSubgraph)
+
+			return Network{URLs: []string{subgraph}}, network.BlockSubgraph != "" && ok
+		},
+	},
+}
+
+// defaultLookups are the block lookup backends tried by default, in order.
+var defaultLookups = []string{"etherscan", "moralis", "covalent", "subgraph"}
+
+// parseLookups checks the names of the block lookup backends given to --lookups.
+func parseLookups(names []string) ([]string, error) {
+	var backends []string
+
+	for _, name := range names {
+		if name == "none" {
+			continue
+		}
+
+		if _, ok := lookupBackends[name]; !ok || !slices.Contains(provider.LookupTypes(), name) {
+			return nil, fmt.Errorf("invalid lookup %q: must be one of %s, or none", name, strings.Join(defaultLookups, ", "))
+		}
+
+		backends = append(backends, name)
+	}
+
+	return backends, nil
+}
+
+// evmChain returns whether network is an EVM chain with a chain ID, which indexers serving many chains know it by.
+func evmChain(network Network) bool {
+	return network.Type == "ethereum" && network.ChainID != 0
+}
+
+// lookupBlock returns the block of network at timestamp found by the first of backends that serves the network, as
+// the closest block to pick from. Blocks are only returned once the endpoints of the network confirm them, so that
+// the search of the endpoints remains the fallback whenever the backends are unavailable or wrong.
+func lookupBlock(ctx context.Context, network Network, finder provider.Source, timestamp int64, backends []string) (blockfinder.BlockRef, bool) {
+	// Mock chains served from fixtures aren't indexed by any backend
+	if activeFixtures != nil {
+		return blockfinder.BlockRef{}, false
+	}
+
+	for _, name := range backends {
+		backend := lookupBackends[name]
+
+		apiKey := ""
+		if backend.APIKeyEnv != "" {
+			if apiKey = os.Getenv(backend.APIKeyEnv); apiKey == "" {
+				continue
+			}
+		}
+
+		apiURL := os.Getenv(backend.URLEnv)
+		if apiURL == "" {
+			apiURL = backend.DefaultURL
+		}
+
+		lookup, ok := backend.endpoint(network, apiURL, apiKey)
+		if !ok {
+			continue
+		}
+
+		lookup.Name, lookup.Proxy = network.Name+" "+name, network.Proxy
+
+		logger := slog.With("network", network.Name, "lookup", name, "target", timestamp)
+
+		number, err := lookupBlockAfter(ctx, name, lookup, timestamp)
+		if err != nil {
+			logger.Warn("Error looking up block, trying the next lookup or searching the endpoints", "error", err)
+			continue
+		}
+
+		block, err := confirmBlockAfter(ctx, finder, number, timestamp)
+		if err != nil {
+			logger.Warn("Looked up block not confirmed by the endpoints, trying the next lookup or searching them", "block", number, "error", err)
+			continue
+		}
+
+		logger.Debug("Found block with lookup", "block", block.Number)
+
+		return block, true
+	}
+
+	return blockfinder.BlockRef{}, false
+}
+
+// lookupBlockAfter asks the lookup backend called name, reached as lookup, for the block at timestamp.
+func lookupBlockAfter(ctx context.Context, name string, lookup Network, timestamp int64) (int64, error) {
+	config, err := lookup.providerConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	backend, conn, err := provider.DialLookup(name, config)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return backend.BlockAfter(ctx, timestamp)
+}
+
+// confirmBlockAfter checks on finder that the block at number, or the one after it, is the first block at or
+// after timestamp, returning it with its timestamp. Lookups may answer the last block before timestamp instead.
+func confirmBlockAfter(ctx context.Context, finder provider.Source, number, timestamp int64) (blockfinder.BlockRef, error) {
+	block := blockfinder.BlockRef{Number: number}
+
+	var err error
+	if block.Timestamp, err = finder.BlockTimestamp(ctx, number); err != nil {
+		return blockfinder.BlockRef{}, err
+	}
+
+	if block.Timestamp < timestamp {
+		next := blockfinder.BlockRef{Number: number + 1}
+		if next.Timestamp, err = finder.BlockTimestamp(ctx, next.Number); err != nil {
+			return blockfinder.BlockRef{}, err
+		}
+
+		if next.Timestamp < timestamp {
+			return blockfinder.BlockRef{}, fmt.Errorf("block %d at %d is still before the target", next.Number, next.Timestamp)
+		}
+
+		return next, nil
+	}
+
+	if number == 0 {
+		return block, nil
+	}
+
+	previous, err := finder.BlockTimestamp(ctx, number-1)
+	if err != nil {
+		return blockfinder.BlockRef{}, err
+	}
+
+	if previous >= timestamp {
+		return blockfinder.BlockRef{}, fmt.Errorf("block %d at %d is at or after the target already", number-1, previous)
+	}
+
+	return block, nil
+}
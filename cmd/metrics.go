@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"get-node-start-block/pkg/metrics"
+)
+
+var (
+	metricsRegistry = metrics.NewRegistry()
+
+	rpcCallsMetric = metricsRegistry.NewCounterVec("node_start_block_rpc_calls_total",
+		"Requests sent to the endpoints of a network.", "network")
+	searchDurationMetric = metricsRegistry.NewHistogramVec("node_start_block_search_duration_seconds",
+		"Time spent resolving the start block of a network.", metrics.DefBuckets, "network")
+	resolutionsMetric = metricsRegistry.NewCounterVec("node_start_block_resolutions_total",
+		"Attempts at resolving the start block of a network, by outcome (success, error or not_configured).", "network", "outcome")
+	endpointErrorsMetric = metricsRegistry.NewCounterVec("node_start_block_endpoint_errors_total",
+		"Failed requests to an endpoint of a network.", "network", "endpoint")
+	resolvedBlockMetric = metricsRegistry.NewGaugeVec("node_start_block_resolved_block",
+		"Latest start block resolved for a network.", "network")
+	resolvedDeltaMetric = metricsRegistry.NewGaugeVec("node_start_block_resolved_block_delta_seconds",
+		"Seconds between the latest start block resolved for a network and its target, negative for blocks before it.", "network")
+	lastSuccessMetric = metricsRegistry.NewGaugeVec("node_start_block_last_success_timestamp_seconds",
+		"Unix time a network was last resolved successfully.", "network")
+)
+
+// recordMetrics adds the outcome of resolving networks to the metrics.
+func recordMetrics(results []resolution) {
+	for _, result := range results {
+		network := result.Network.Name
+
+		rpcCallsMetric.Add(float64(result.RPCCalls), network)
+		searchDurationMetric.Observe(result.Duration.Seconds(), network)
+
+		for _, health := range result.Endpoints {
+			if health.Failures > 0 {
+				endpointErrorsMetric.Add(float64(health.Failures), network, health.URL)
+			}
+		}
+
+		if result.notConfigured() {
+			resolutionsMetric.Inc(network, "not_configured")
+			continue
+		}
+
+		if result.Err != nil {
+			resolutionsMetric.Inc(network, "error")
+			continue
+		}
+
+		resolutionsMetric.Inc(network, "success")
+		resolvedBlockMetric.Set(float64(result.Block), network)
+		resolvedDeltaMetric.Set(float64(result.BlockTimestamp-result.Target), network)
+		lastSuccessMetric.Set(float64(time.Now().Unix()), network)
+	}
+}
+
+// serveMetrics serves the metrics on /metrics at address until ctx is done. The listener is opened
+// before returning, so a taken address fails straight away.
+func serveMetrics(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsRegistry.Handler())
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Error serving metrics", "error", err)
+		}
+	}()
+
+	slog.Info("Serving metrics", "address", listener.Addr().String())
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// configVersion is the schema version of the config files written.
+//
+//   - 1: network_start_block maps every network to its start block number. Files without a version are version 1.
+//   - 2: network_start_block maps every network to a StartBlock object.
+const configVersion = 2
+
+// configMigrations upgrade the top-level fields of config files of a version to the next version.
+var configMigrations = map[int]func(fields map[string]json.RawMessage) error{
+	1: migrateStartBlockObjects,
+}
+
+// migrateConfig upgrades the config file content to configVersion, returning it unchanged if it's up to date.
+func migrateConfig(content []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid version: %v", err)
+		}
+	}
+
+	if version == configVersion {
+		return content, nil
+	}
+
+	if version < 1 || version > configVersion {
+		return nil, fmt.Errorf("unsupported version %d, expected at most %d", version, configVersion)
+	}
+
+	for ; version < configVersion; version++ {
+		if err := configMigrations[version](fields); err != nil {
+			return nil, fmt.Errorf("error migrating from version %d: %v", version, err)
+		}
+	}
+
+	fields["version"], _ = json.Marshal(configVersion)
+
+	return json.Marshal(fields)
+}
+
+// migrateStartBlockObjects turns the start block numbers of version 1 into StartBlock objects.
+// Nothing else is known about them, so only their block is set.
+func migrateStartBlockObjects(fields map[string]json.RawMessage) error {
+	raw, ok := fields["network_start_block"]
+	if !ok {
+		return nil
+	}
+
+	var numbers map[string]int64
+	if err := json.Unmarshal(raw, &numbers); err != nil {
+		return fmt.Errorf("invalid network_start_block: %v", err)
+	}
+
+	startBlocks := make(map[string]StartBlock, len(numbers))
+	for network, block := range numbers {
+		startBlocks[network] = StartBlock{Block: block}
+	}
+
+	var err error
+	fields["network_start_block"], err = json.Marshal(startBlocks)
+
+	return err
+}
+
+// compatConfig is the layout of version 1 config files, written for consumers that don't read later versions.
+type compatConfig struct {
+	NetworkStartBlock         map[string]int64       `json:"network_start_block"`
+	NetworkBlockTime          map[string]float64     `json:"network_block_time,omitempty"`
+	NetworkGenesisTimestamp   map[string]int64       `json:"network_genesis_timestamp,omitempty"`
+	NetworkFarcasterTimestamp map[string]int64       `json:"network_farcaster_timestamp,omitempty"`
+	Networks                  []NetworkConfig        `json:"networks,omitempty"`
+	Settings                  map[string]interface{} `json:"settings,omitempty"`
+}
+
+// compat returns config in the layout of version 1 config files.
+func (c *Config) compat() compatConfig {
+	return compatConfig{
+		NetworkStartBlock:         c.StartBlockNumbers(),
+		NetworkBlockTime:          c.NetworkBlockTime,
+		NetworkGenesisTimestamp:   c.NetworkGenesisTimestamp,
+		NetworkFarcasterTimestamp: c.NetworkFarcasterTimestamp,
+		Networks:                  c.Networks,
+		Settings:                  c.Settings,
+	}
+}
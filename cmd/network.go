@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+	"get-node-start-block/pkg/provider"
+)
+
+type Network struct {
+	Name string
+	URLs []string
+	Type string
+	// RateLimit is the maximum number of requests per second to the network, or 0 for no limit.
+	RateLimit float64
+	// MinBlock is the lowest block the node can index, or 0 if it can index the whole chain.
+	MinBlock int64
+	// ScanWindow is the number of blocks around the block found by a search that are checked, or 0 to trust it.
+	ScanWindow int64
+	// EpochContract is the address of the contract tracking the epochs of the network, if any.
+	EpochContract string
+	// Env is the environment variable the endpoints of the network are taken from, if any.
+	Env string
+	// Worker is the RSS3 Node worker indexing the network.
+	Worker string
+	// ChainID is the chain ID the endpoints of the network must report, or 0 to skip the check.
+	ChainID int64
+	// AnchorContract is the address of the contract whose deployment block is the earliest start block, if any.
+	AnchorContract string
+	// AnchorEvent is the topic of the log of AnchorContract whose first block is the earliest start block instead,
+	// if any.
+	AnchorEvent string
+	// Target overrides the target time of runs for the network, as a timestamp, genesisTarget or deploymentTarget,
+	// if set.
+	Target string
+	// Estimate is the reference block to estimate start blocks from offline, if the config sets one.
+	Estimate *EstimateReference
+	// Headers are added to every request to the endpoints, with references to environment variables unexpanded.
+	Headers map[string]string
+	// Proxy is the URL of the proxy to the endpoints, with references to environment variables unexpanded, if any.
+	Proxy string
+	// CrossCheck are the second sources resolved start blocks are checked against, if any.
+	CrossCheck []CrossCheckSource
+	// BlockSubgraph is the URL of the blocks subgraph to look blocks up in, unexpanded, if any.
+	BlockSubgraph string
+	// L1Anchor is the L1 the network derives its blocks from, if it is an optimistic rollup.
+	L1Anchor *L1Anchor
+	// L1 is the network named by L1Anchor, if it is among the networks.
+	L1 *Network
+	// Required fails the run without writing the config if the network isn't resolved, even for lack of endpoints.
+	Required bool
+}
+
+// genesisTarget is the target of networks starting at the first block of their chain.
+const genesisTarget = "genesis"
+
+// deploymentTarget is the target of networks starting at the deployment of the contracts their worker indexes.
+const deploymentTarget = "deployment"
+
+// deploymentContracts are the built-in contracts networks targeting deploymentTarget without an anchor contract
+// start at the deployment of, by network.
+var deploymentContracts = map[string][]string{
+	// The Crossbell worker indexes the Character (Web3Entry) and LinkList contracts, deployed together at launch
+	"crossbell": {"0xa6f969045641Cf486a747A2688F3a5A6d43cd0D8", "0xFc8C75bD5c26F50798758f387B698f207a016b6A"},
+}
+
+// defaultWorker is the RSS3 Node worker of networks that don't name one.
+const defaultWorker = "core"
+
+// defaultNetworks are the networks of the mainnet profile.
+var defaultNetworks = []NetworkConfig{
+	{Name: "ethereum", Type: "ethereum", ChainID: 1, Env: "ETHEREUM_RPC_URL"},
+	{Name: "polygon", Type: "ethereum", ChainID: 137, Env: "POLYGON_RPC_URL"},
+	// The Lens worker starts no earlier than the deployment of the LensHub proxy on Polygon
+	{Name: "lens", Type: "ethereum", ChainID: 137, Env: "POLYGON_RPC_URL", Worker: "lens", AnchorContract: "0xDb46d1Dc155634FbC732f92E853b10B288AD5a1d"},
+	{Name: "avax", Type: "ethereum", ChainID: 43114, Env: "AVALANCHE_RPC_URL"},
+	// Blocks before the Bedrock upgrade were migrated from the legacy OVM chain
+	{Name: "optimism", Type: "ethereum", ChainID: 10, Env: "OPTIMISM_RPC_URL", MinBlock: 105235063, L1Anchor: &L1Anchor{Network: "ethereum", Rollup: "op-stack"}},
+	// Blocks before the Nitro genesis block were migrated from Arbitrum Classic, and the sequencer may stamp blocks
+	// ahead of the ones following them
+	{Name: "arbitrum", Type: "ethereum", ChainID: 42161, Env: "ARBITRUM_RPC_URL", MinBlock: 22207817, ScanWindow: 32, L1Anchor: &L1Anchor{Network: "ethereum", Rollup: "arbitrum"}},
+	{Name: "gnosis", Type: "ethereum", ChainID: 100, Env: "GNOSIS_RPC_URL"},
+	{Name: "linea", Type: "ethereum", ChainID: 59144, Env: "LINEA_RPC_URL"},
+	{Name: "binance-smart-chain", Type: "ethereum", ChainID: 56, Env: "BSC_RPC_URL"},
+	{Name: "base", Type: "ethereum", ChainID: 8453, Env: "BASE_RPC_URL", L1Anchor: &L1Anchor{Network: "ethereum", Rollup: "op-stack"}},
+	{Name: "crossbell", Type: "ethereum", ChainID: 3737, Env: "CROSSBELL_RPC_URL"},
+	// Start blocks on VSL are aligned to the epochs of its Settlement contract
+	{Name: "vsl", Type: "vsl", ChainID: 12553, Env: "VSL_RPC_URL"},
+	{Name: "x-layer", Type: "ethereum", ChainID: 196, Env: "XLAYER_RPC_URL"},
+	// Public endpoints are used for these L2s unless their environment variable is set
+	{Name: "zksync-era", Type: "ethereum", ChainID: 324, Env: "ZKSYNC_RPC_URL", URLs: []string{"https://mainnet.era.zksync.io"}},
+	{Name: "scroll", Type: "ethereum", ChainID: 534352, Env: "SCROLL_RPC_URL", URLs: []string{"https://rpc.scroll.io"}},
+	{Name: "mantle", Type: "ethereum", ChainID: 5000, Env: "MANTLE_RPC_URL", URLs: []string{"https://rpc.mantle.xyz"}},
+	{Name: "blast", Type: "ethereum", ChainID: 81457, Env: "BLAST_RPC_URL", URLs: []string{"https://rpc.blast.io"}},
+	{Name: "mode", Type: "ethereum", ChainID: 34443, Env: "MODE_RPC_URL", URLs: []string{"https://mainnet.mode.network"}},
+	{Name: "starknet", Type: "starknet", Env: "STARKNET_RPC_URL"},
+	// Start points are ledger versions on Aptos, and checkpoint sequence numbers on Sui
+	{Name: "aptos", Type: "aptos", ChainID: 1, Env: "APTOS_RPC_URL"},
+	{Name: "sui", Type: "sui", Env: "SUI_RPC_URL"},
+	{Name: "ton", Type: "ton", Env: "TON_RPC_URL"},
+	{Name: "filecoin", Type: "filecoin", Env: "FILECOIN_RPC_URL"},
+	{Name: "algorand", Type: "algorand", Env: "ALGORAND_RPC_URL"},
+	{Name: "stellar", Type: "stellar", Env: "STELLAR_HORIZON_URL"},
+	// Ledgers before 32570 were lost in the early days of the XRP Ledger
+	{Name: "xrpl", Type: "xrpl", Env: "XRPL_RPC_URL", MinBlock: 32570},
+	// The public mirror node is used unless HEDERA_MIRROR_URL is set
+	{Name: "hedera", Type: "hedera", Env: "HEDERA_MIRROR_URL", URLs: []string{"https://mainnet-public.mirrornode.hedera.com"}},
+	// Public gateways are used unless ARWEAVE_RPC_URL is set
+	{Name: "arweave", Type: "arweave", Env: "ARWEAVE_RPC_URL", URLs: []string{"https://arweave.net", "https://ar-io.net", "https://g8way.io"}, Worker: "mirror"},
+	{Name: "solana", Type: "solana", Env: "SOLANA_RPC_URL"},
+	// TronGrid serves the full node HTTP API publicly, at a low rate limit without an API key
+	{Name: "tron", Type: "tron", Env: "TRON_RPC_URL", URLs: []string{"https://api.trongrid.io"}},
+	{Name: "cosmos", Type: "cosmos", Env: "COSMOS_RPC_URL"},
+	{Name: "osmosis", Type: "cosmos", Env: "OSMOSIS_RPC_URL"},
+	// Data availability layers are found through the RPC of the chains they are built on
+	{Name: "celestia", Type: "cosmos", Env: "CELESTIA_RPC_URL"},
+	{Name: "avail", Type: "substrate", Env: "AVAIL_RPC_URL"},
+	// bitcoind credentials aren't always available, so the Esplora API is accepted instead
+	{Name: "bitcoin", Type: "bitcoin", Env: "BITCOIN_RPC_URL", FallbackEnv: "BITCOIN_ESPLORA_URL", FallbackType: "esplora"},
+	{Name: "dogecoin", Type: "bitcoin", Env: "DOGECOIN_RPC_URL", FallbackEnv: "DOGECOIN_BLOCKBOOK_URL", FallbackType: "blockbook"},
+	{Name: "litecoin", Type: "bitcoin", Env: "LITECOIN_RPC_URL", FallbackEnv: "LITECOIN_BLOCKBOOK_URL", FallbackType: "blockbook"},
+	{Name: "near", Type: "near", Env: "NEAR_RPC_URL"},
+	{Name: "polkadot", Type: "substrate", Env: "POLKADOT_RPC_URL", FallbackEnv: "POLKADOT_SIDECAR_URL", FallbackType: "sidecar"},
+	{Name: "kusama", Type: "substrate", Env: "KUSAMA_RPC_URL", FallbackEnv: "KUSAMA_SIDECAR_URL", FallbackType: "sidecar"},
+	{Name: "farcaster", Type: "farcaster", Env: "FARCASTER_HUB_URL"},
+	{Name: "ethereum-beacon", Type: "beacon", Env: "ETHEREUM_BEACON_URL"},
+}
+
+// networks returns the networks to resolve, as listed in the networks section of config or by the active profile,
+// with endpoints taken from the environment.
+func networks(config *Config) []Network {
+	networkConfigs := config.Networks
+	if len(networkConfigs) == 0 {
+		networkConfigs = activeProfile.Networks
+	}
+
+	result := make([]Network, 0, len(networkConfigs))
+	for _, networkConfig := range networkConfigs {
+		network := networkConfig.network()
+		if activeFixtures != nil {
+			network = activeFixtures.serve(network)
+		}
+
+		result = append(result, network)
+	}
+
+	// Rollups are resolved through their L1 as configured, even when it is left out of a run
+	for index := range result {
+		if result[index].L1Anchor == nil {
+			continue
+		}
+
+		for _, l1 := range result {
+			if l1.Name == result[index].L1Anchor.Network {
+				l1 := l1
+				result[index].L1 = &l1
+			}
+		}
+	}
+
+	return result
+}
+
+// lookupNetwork returns the network called name among the networks of config.
+func lookupNetwork(config *Config, name string) (Network, error) {
+	var names []string
+
+	for _, network := range networks(config) {
+		if network.Name == name {
+			return network, nil
+		}
+
+		names = append(names, network.Name)
+	}
+
+	return Network{}, fmt.Errorf("unknown network %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// network returns the network described by c. Endpoints in the environment variable Env take precedence
+// over the endpoints of the presets whose API keys are set, followed by the URLs listed in the config, and the
+// fallback is only used when none of them is set. URLs referencing unset variables are left out.
+func (c NetworkConfig) network() Network {
+	urls := endpointsFromEnv(c.Env)
+	if len(urls) == 0 {
+		urls = append(presetEndpoints(c.Name), expandEndpoints(c.URLs)...)
+	}
+
+	// The rate limit was validated when loading the config
+	rateLimit, _ := parseRateLimit(c.RateLimit)
+
+	network := Network{
+		Name:          c.Name,
+		URLs:          urls,
+		Type:          c.Type,
+		RateLimit:     rateLimit,
+		MinBlock:      c.MinBlock,
+		ScanWindow:    c.ScanWindow,
+		EpochContract: c.EpochContract,
+		Env:           c.Env,
+		Worker:        c.Worker,
+		ChainID:       c.ChainID,
+
+		AnchorContract: c.AnchorContract,
+		AnchorEvent:    eventTopic(c.AnchorEvent),
+		Target:         c.Target,
+		Estimate:       c.Estimate,
+		L1Anchor:       c.L1Anchor,
+		Headers:        c.Headers,
+		Proxy:          c.Proxy,
+		CrossCheck:     c.CrossCheck,
+		BlockSubgraph:  c.BlockSubgraph,
+	}
+
+	if len(urls) == 0 && c.FallbackEnv != "" {
+		network.URLs, network.Type, network.Env = endpointsFromEnv(c.FallbackEnv), c.FallbackType, c.FallbackEnv
+	}
+
+	if network.Worker == "" {
+		network.Worker = defaultWorker
+	}
+
+	return network
+}
+
+// endpointsFromEnv splits the comma-separated list of endpoint URLs in the environment variable key.
+func endpointsFromEnv(key string) []string {
+	var urls []string
+
+	for _, url := range strings.Split(os.Getenv(key), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+// deploymentContracts returns the contracts n starts at the deployment of when targeting deploymentTarget, its
+// anchor contract or else its built-in contracts.
+func (n Network) deploymentContracts() []string {
+	if n.AnchorContract != "" {
+		return []string{n.AnchorContract}
+	}
+
+	return deploymentContracts[n.Name]
+}
+
+// errNotConfigured is the error of networks without endpoints, which are left out of runs unless required rather
+// than failing them, as operators leave the variables of networks they don't index empty.
+var errNotConfigured = errors.New("not configured")
+
+// notConfiguredError returns the error of network having no endpoints, naming where they are taken from.
+func notConfiguredError(network Network) error {
+	if network.Env == "" {
+		return fmt.Errorf("%w: the config lists no urls", errNotConfigured)
+	}
+
+	return fmt.Errorf("%w: %s is empty and the config lists no urls", errNotConfigured, network.Env)
+}
+
+// selectNetworks returns networkList without the networks named in skip, marking the networks named in require as
+// required. Names must be among networkList.
+func selectNetworks(networkList []Network, skip, require []string) ([]Network, error) {
+	names := make([]string, 0, len(networkList))
+	for _, network := range networkList {
+		names = append(names, network.Name)
+	}
+
+	for _, name := range append(slices.Clone(skip), require...) {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown network %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	selected := make([]Network, 0, len(networkList))
+	for _, network := range networkList {
+		if slices.Contains(skip, network.Name) {
+			slog.Info("Skipping network", "network", network.Name)
+			continue
+		}
+
+		network.Required = slices.Contains(require, network.Name)
+		selected = append(selected, network)
+	}
+
+	return selected, nil
+}
+
+// addSelectFlags adds the --skip and --require flags read by selectFlags to cmd.
+func addSelectFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("skip", nil, "networks to leave out, like --skip crossbell (resolve keeps their start blocks as they were)")
+	cmd.Flags().StringSlice("require", nil, "networks that must succeed, failing the command if they have no endpoints, like --require ethereum,base (networks without endpoints are left out otherwise)")
+}
+
+// selectFlags returns the networks to skip and to require set by the flags added by addSelectFlags.
+func selectFlags(cmd *cobra.Command) (skip, require []string, err error) {
+	skip, _ = cmd.Flags().GetStringSlice("skip")
+	require, _ = cmd.Flags().GetStringSlice("require")
+
+	for _, name := range skip {
+		if slices.Contains(require, name) {
+			return nil, nil, fmt.Errorf("network %q can't be both skipped and required", name)
+		}
+	}
+
+	return skip, require, nil
+}
+
+// selectedNetworks returns the networks of config selected by the flags added by addSelectFlags.
+func selectedNetworks(cmd *cobra.Command, config *Config) ([]Network, error) {
+	skip, require, err := selectFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return selectNetworks(networks(config), skip, require)
+}
+
+// dialNetwork connects to network and returns a block source for it, along with its connection to be closed once done.
+func dialNetwork(_ context.Context, network Network) (provider.Source, provider.Connection, error) {
+	config, err := network.providerConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider.Dial(network.Type, config)
+}
+
+// providerConfig returns the config connecting to the endpoints of n.
+func (n Network) providerConfig() (provider.Config, error) {
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if n.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(n.RateLimit), max(1, int(n.RateLimit)))
+	}
+
+	headers, proxy, err := n.transport()
+	if err != nil {
+		return provider.Config{}, err
+	}
+
+	return provider.Config{
+		Name:          n.Name,
+		URLs:          n.URLs,
+		Retry:         retryPolicy,
+		Limiter:       limiter,
+		CallTimeout:   callTimeout,
+		Racing:        racing,
+		MinHeight:     n.MinBlock,
+		ScanWindow:    n.ScanWindow,
+		EpochContract: n.EpochContract,
+		Headers:       headers,
+		Proxy:         proxy,
+	}, nil
+}
+
+// transport returns the headers and proxy of requests to the endpoints of n, expanding the environment variables
+// they reference. Unlike endpoints, which are skipped, they fail the network when a variable is unset, as requests
+// without them would be rejected or bypass the proxy.
+func (n Network) transport() (http.Header, *url.URL, error) {
+	headers := make(http.Header, len(n.Headers))
+	for key, template := range n.Headers {
+		value, ok := expandEndpoint(template)
+		if !ok {
+			return nil, nil, fmt.Errorf("header %s references an unset environment variable", key)
+		}
+
+		headers.Set(key, value)
+	}
+
+	if n.Proxy == "" {
+		return headers, nil, nil
+	}
+
+	rawProxy, ok := expandEndpoint(n.Proxy)
+	if !ok {
+		return nil, nil, fmt.Errorf("proxy references an unset environment variable")
+	}
+
+	proxy, err := url.Parse(rawProxy)
+	if err != nil || proxy.Host == "" {
+		return nil, nil, fmt.Errorf("invalid proxy %s", endpoint.Redact(rawProxy))
+	}
+
+	return headers, proxy, nil
+}
+
+// validProxy returns whether proxy, before expanding the environment variables it references, is a URL of a
+// proxy the endpoints can be reached through.
+func validProxy(proxy string) bool {
+	scheme, _, ok := strings.Cut(proxy, "://")
+
+	return ok && slices.Contains([]string{"http", "https", "socks5"}, strings.ToLower(scheme))
+}
+
+// verifyChainID checks that the endpoints of network serve the chain it expects, so that a start block
+// is never derived from another chain whose endpoint was configured by mistake.
+func verifyChainID(ctx context.Context, network Network, source provider.Source) error {
+	if network.ChainID == 0 {
+		return nil
+	}
+
+	identifier, ok := source.(blockfinder.ChainIdentifier)
+	if !ok {
+		return fmt.Errorf("network type %q has no chain ID to verify", network.Type)
+	}
+
+	chainID, err := identifier.ChainID(ctx)
+	if errors.Is(err, blockfinder.ErrNoChainID) {
+		return fmt.Errorf("network type %q has no chain ID to verify", network.Type)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if chainID != network.ChainID {
+		return fmt.Errorf("endpoint serves chain ID %d, expected %d: check the endpoint URL", chainID, network.ChainID)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"get-node-start-block/pkg/endpoint"
+)
+
+const (
+	// webhooksEnv holds the comma-separated webhook URLs notified when --webhook is not given,
+	// since webhook URLs are secrets better kept out of command lines.
+	webhooksEnv = "NOTIFY_WEBHOOK_URLS"
+	// webhookTimeout bounds the time spent notifying a single webhook.
+	webhookTimeout = 10 * time.Second
+	// maxListedFailures is the number of failed networks detailed in a notification, the others being counted.
+	maxListedFailures = 10
+	// maxDiscordMessage is the longest message Discord accepts, in characters.
+	maxDiscordMessage = 2000
+)
+
+// runSummary is what a resolve run did, for notifications.
+type runSummary struct {
+	Target  int64
+	Results []resolution
+	// Changes are the changes to the start blocks, as described by diffStartBlocks.
+	Changes []string
+}
+
+// message describes the run in a few lines of plain text, along with err if it failed.
+func (s runSummary) message(err error) string {
+	var builder strings.Builder
+
+	if s.Results == nil {
+		fmt.Fprintf(&builder, "Start block resolution failed: %v", err)
+		return builder.String()
+	}
+
+	var failed []resolution
+	for _, result := range s.Results {
+		if result.failed() {
+			failed = append(failed, result)
+		}
+	}
+
+	configured := len(s.Results) - countNotConfigured(s.Results)
+
+	fmt.Fprintf(&builder, "Start blocks resolved for %s: %d of %d networks resolved, %d failed.",
+		time.Unix(s.Target, 0).UTC().Format(time.RFC3339), configured-len(failed), configured, len(failed))
+
+	if notConfigured := len(s.Results) - configured; notConfigured > 0 {
+		fmt.Fprintf(&builder, " %d not configured.", notConfigured)
+	}
+
+	if err != nil {
+		fmt.Fprintf(&builder, "\nRun failed: %v", err)
+	}
+
+	if len(failed) > 0 {
+		builder.WriteString("\n\nFailed networks:")
+
+		for _, result := range failed[:min(len(failed), maxListedFailures)] {
+			fmt.Fprintf(&builder, "\n- %s: %v", result.Network.Name, result.Err)
+		}
+
+		if len(failed) > maxListedFailures {
+			fmt.Fprintf(&builder, "\n- and %d more", len(failed)-maxListedFailures)
+		}
+	}
+
+	if len(s.Changes) == 0 {
+		builder.WriteString("\n\nNo changes to start blocks.")
+	} else {
+		builder.WriteString("\n\nChanges to start blocks:\n" + strings.Join(s.Changes, "\n"))
+	}
+
+	return builder.String()
+}
+
+// notifyWebhooks posts message to every webhook in urls, in the payload format of Discord for Discord webhooks
+// and of Slack otherwise. Failures are logged, as a notification going missing shouldn't fail the run.
+func notifyWebhooks(ctx context.Context, urls []string, message string) {
+	// Runs stopped by a signal are still reported
+	ctx = context.WithoutCancel(ctx)
+
+	for _, webhookURL := range urls {
+		if err := postWebhook(ctx, webhookURL, message); err != nil {
+			slog.Warn("Error notifying webhook", "webhook", endpoint.Redact(webhookURL), "error", err)
+			continue
+		}
+
+		slog.Debug("Webhook notified", "webhook", endpoint.Redact(webhookURL))
+	}
+}
+
+// postWebhook posts message to a single webhook.
+func postWebhook(ctx context.Context, webhookURL, message string) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	payload := map[string]string{"text": message}
+	if isDiscordWebhook(webhookURL) {
+		if runes := []rune(message); len(runes) > maxDiscordMessage {
+			message = string(runes[:maxDiscordMessage-1]) + "…"
+		}
+
+		payload = map[string]string{"content": message}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+		return fmt.Errorf("unexpected status code %d: %s", response.StatusCode, content)
+	}
+
+	return nil
+}
+
+// isDiscordWebhook reports whether webhookURL is a Discord webhook, which expects its message in "content".
+func isDiscordWebhook(webhookURL string) bool {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return false
+	}
+
+	host := parsed.Hostname()
+
+	return host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com")
+}
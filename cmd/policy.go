@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Exit codes of the tool, for CI to tell runs that failed outright from runs that left some networks behind.
+const (
+	exitFatal   = 1
+	exitPartial = 2
+)
+
+// exitError is an error the tool exits with a specific code for.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// failurePolicy decides whether a run with failed networks still writes its config.
+type failurePolicy struct {
+	// FailOnError refuses to write the config if any network failed.
+	FailOnError bool
+	// MinSuccess is the lowest share of networks, from 0 to 1, that must be resolved for the config to be written.
+	MinSuccess float64
+}
+
+// parseMinSuccess parses a share of networks like "90%" or "0.9" into a number from 0 to 1.
+// An empty share is 0, accepting any number of failures.
+func parseMinSuccess(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	number, scale := value, 1.0
+	if strings.HasSuffix(value, "%") {
+		number, scale = strings.TrimSuffix(value, "%"), 100
+	}
+
+	share, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || share < 0 || share/scale > 1 {
+		return 0, fmt.Errorf("invalid minimum success %q: must be a percentage like \"90%%\" or a share like 0.9", value)
+	}
+
+	return share / scale, nil
+}
+
+// check returns a fatal error if results break the policy, or if a required network wasn't resolved. Networks that
+// aren't configured count neither as resolved nor as failed.
+func (p failurePolicy) check(results []resolution) error {
+	var missing []string
+	for _, result := range results {
+		if result.Network.Required && result.Err != nil {
+			missing = append(missing, result.Network.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("required networks not resolved: %s, not writing the config", strings.Join(missing, ", "))
+	}
+
+	failed := countFailed(results)
+	if failed == 0 {
+		return nil
+	}
+
+	configured := len(results) - countNotConfigured(results)
+
+	if p.FailOnError {
+		return fmt.Errorf("%d of %d networks failed, not writing the config", failed, configured)
+	}
+
+	if succeeded := float64(configured-failed) / float64(configured); succeeded < p.MinSuccess {
+		return fmt.Errorf("only %.1f%% of networks were resolved, below the minimum of %.1f%%, not writing the config",
+			succeeded*100, p.MinSuccess*100)
+	}
+
+	return nil
+}
+
+// partialFailure returns an error exiting with exitPartial if some of results failed, or nil if none did.
+func partialFailure(results []resolution) error {
+	failed := countFailed(results)
+	if failed == 0 {
+		return nil
+	}
+
+	return &exitError{
+		code: exitPartial,
+		err:  fmt.Errorf("%d of %d networks failed, leaving their start blocks as they were", failed, len(results)-countNotConfigured(results)),
+	}
+}
+
+// countFailed returns the number of results that failed, leaving out networks that aren't configured.
+func countFailed(results []resolution) int {
+	var failed int
+	for _, result := range results {
+		if result.failed() {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// countNotConfigured returns the number of results of networks left out for lack of endpoints.
+func countNotConfigured(results []resolution) int {
+	var notConfigured int
+	for _, result := range results {
+		if result.notConfigured() {
+			notConfigured++
+		}
+	}
+
+	return notConfigured
+}
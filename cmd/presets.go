@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"regexp"
+)
+
+// endpointPreset is a hosted RPC provider serving many networks, whose endpoint URLs only differ by network
+// and embed the API key of the operator.
+type endpointPreset struct {
+	// Provider names the provider in logs.
+	Provider string
+	// Templates are the endpoint URL templates of the networks the provider serves, by network name.
+	// Endpoints are only used once every variable their template references is set.
+	Templates map[string]string
+}
+
+// endpointPresets are the hosted RPC providers whose endpoints are added to the networks they serve once the
+// operator sets their API keys, so that one or two keys replace the endpoint URLs of every network.
+// They are tried before the URLs of the config, which are public endpoints in the built-in profiles.
+var endpointPresets = []endpointPreset{
+	{
+		Provider: "alchemy",
+		Templates: map[string]string{
+			"ethereum":   "https://eth-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"polygon":    "https://polygon-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"optimism":   "https://opt-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"arbitrum":   "https://arb-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"base":       "https://base-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"linea":      "https://linea-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"zksync-era": "https://zksync-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"scroll":     "https://scroll-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+			"blast":      "https://blast-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}",
+		},
+	},
+	{
+		Provider: "infura",
+		Templates: map[string]string{
+			"ethereum": "https://mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"polygon":  "https://polygon-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"optimism": "https://optimism-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"arbitrum": "https://arbitrum-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"base":     "https://base-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"linea":    "https://linea-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"avax":     "https://avalanche-mainnet.infura.io/v3/${INFURA_API_KEY}",
+			"blast":    "https://blast-mainnet.infura.io/v3/${INFURA_API_KEY}",
+		},
+	},
+	{
+		// QuickNode endpoints are named by the operator, so both the name and the token are needed
+		Provider: "quicknode",
+		Templates: map[string]string{
+			"ethereum":            "https://${QUICKNODE_ENDPOINT}.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"polygon":             "https://${QUICKNODE_ENDPOINT}.matic.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"optimism":            "https://${QUICKNODE_ENDPOINT}.optimism.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"arbitrum":            "https://${QUICKNODE_ENDPOINT}.arbitrum-mainnet.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"base":                "https://${QUICKNODE_ENDPOINT}.base-mainnet.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"avax":                "https://${QUICKNODE_ENDPOINT}.avalanche-mainnet.quiknode.pro/${QUICKNODE_API_KEY}/ext/bc/C/rpc",
+			"binance-smart-chain": "https://${QUICKNODE_ENDPOINT}.bsc.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"gnosis":              "https://${QUICKNODE_ENDPOINT}.xdai.quiknode.pro/${QUICKNODE_API_KEY}/",
+			"solana":              "https://${QUICKNODE_ENDPOINT}.solana-mainnet.quiknode.pro/${QUICKNODE_API_KEY}/",
+		},
+	},
+	{
+		Provider: "ankr",
+		Templates: map[string]string{
+			"ethereum":            "https://rpc.ankr.com/eth/${ANKR_API_KEY}",
+			"polygon":             "https://rpc.ankr.com/polygon/${ANKR_API_KEY}",
+			"avax":                "https://rpc.ankr.com/avalanche/${ANKR_API_KEY}",
+			"optimism":            "https://rpc.ankr.com/optimism/${ANKR_API_KEY}",
+			"arbitrum":            "https://rpc.ankr.com/arbitrum/${ANKR_API_KEY}",
+			"gnosis":              "https://rpc.ankr.com/gnosis/${ANKR_API_KEY}",
+			"linea":               "https://rpc.ankr.com/linea/${ANKR_API_KEY}",
+			"binance-smart-chain": "https://rpc.ankr.com/bsc/${ANKR_API_KEY}",
+			"base":                "https://rpc.ankr.com/base/${ANKR_API_KEY}",
+			"x-layer":             "https://rpc.ankr.com/xlayer/${ANKR_API_KEY}",
+			"zksync-era":          "https://rpc.ankr.com/zksync_era/${ANKR_API_KEY}",
+			"scroll":              "https://rpc.ankr.com/scroll/${ANKR_API_KEY}",
+			"mantle":              "https://rpc.ankr.com/mantle/${ANKR_API_KEY}",
+			"blast":               "https://rpc.ankr.com/blast/${ANKR_API_KEY}",
+			"solana":              "https://rpc.ankr.com/solana/${ANKR_API_KEY}",
+		},
+	},
+}
+
+// presetAliases are the networks served by the endpoints of another one, like protocols living on a chain.
+var presetAliases = map[string]string{
+	"lens": "polygon",
+}
+
+// templateVariable matches the ${VAR} references of endpoint URL templates.
+var templateVariable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEndpoint replaces the ${VAR} references of the endpoint URL template with the values of the environment
+// variables they name. It returns false if one of them is unset or empty, as the endpoint is then unusable.
+func expandEndpoint(template string) (string, bool) {
+	complete := true
+
+	url := templateVariable.ReplaceAllStringFunc(template, func(reference string) string {
+		value := os.Getenv(templateVariable.FindStringSubmatch(reference)[1])
+		if value == "" {
+			complete = false
+		}
+
+		return value
+	})
+
+	return url, complete
+}
+
+// expandEndpoints expands the endpoint URL templates, dropping the ones referencing unset variables.
+func expandEndpoints(templates []string) []string {
+	var urls []string
+
+	for _, template := range templates {
+		if url, ok := expandEndpoint(template); ok {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+// presetEndpoints returns the endpoints of the presets serving the network called name whose API keys are set.
+func presetEndpoints(name string) []string {
+	served := name
+	if alias, ok := presetAliases[name]; ok {
+		served = alias
+	}
+
+	var urls []string
+
+	for _, preset := range endpointPresets {
+		template, ok := preset.Templates[served]
+		if !ok {
+			continue
+		}
+
+		if url, ok := expandEndpoint(template); ok {
+			slog.Debug("Using endpoint preset", "network", name, "provider", preset.Provider)
+
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// profile is a set of networks to generate start blocks for, such as the mainnets run by production nodes.
+type profile struct {
+	// ConfigPath is the config file used when --config is not given.
+	ConfigPath string
+	// Networks are the networks resolved when the config file has no networks section.
+	Networks []NetworkConfig
+}
+
+// defaultProfile is the profile used when --profile is not given.
+const defaultProfile = "mainnet"
+
+var profiles = map[string]profile{
+	"mainnet": {ConfigPath: "config.json", Networks: defaultNetworks},
+	"testnet": {ConfigPath: "config.testnet.json", Networks: testnetNetworks},
+}
+
+// testnetNetworks are the networks indexed by staging nodes, with their own environment variables
+// so that mainnet and testnet endpoints can live in the same .env file.
+var testnetNetworks = []NetworkConfig{
+	{Name: "sepolia", Type: "ethereum", ChainID: 11155111, Env: "SEPOLIA_RPC_URL"},
+	{Name: "amoy", Type: "ethereum", ChainID: 80002, Env: "POLYGON_AMOY_RPC_URL"},
+	{Name: "fuji", Type: "ethereum", ChainID: 43113, Env: "AVALANCHE_FUJI_RPC_URL"},
+	{Name: "binance-smart-chain-testnet", Type: "ethereum", ChainID: 97, Env: "BSC_TESTNET_RPC_URL"},
+	{Name: "arweave-testnet", Type: "arweave", Env: "ARWEAVE_TESTNET_RPC_URL", Worker: "mirror"},
+	{Name: "celestia-mocha", Type: "cosmos", Env: "CELESTIA_MOCHA_RPC_URL"},
+	{Name: "avail-turing", Type: "substrate", Env: "AVAIL_TURING_RPC_URL"},
+}
+
+// lookupProfile returns the profile called name.
+func lookupProfile(name string) (profile, error) {
+	selected, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		return profile{}, fmt.Errorf("invalid profile %q: must be one of %s", name, strings.Join(names, ", "))
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is how often the progress board is redrawn.
+const progressInterval = 250 * time.Millisecond
+
+// activeBoard is the progress board drawn on stderr, if any, which log messages are written around.
+var activeBoard atomic.Pointer[progressBoard]
+
+// logOutput is where log messages are written: stderr, above the progress board while one is drawn.
+type logOutput struct{}
+
+func (logOutput) Write(p []byte) (int, error) {
+	if board := activeBoard.Load(); board != nil {
+		return board.writeAbove(p)
+	}
+
+	return os.Stderr.Write(p)
+}
+
+// progressBoard draws the live status of the networks resolved by a run on a terminal: what each network is doing,
+// the RPC calls it made and the range of blocks its search is down to.
+type progressBoard struct {
+	locker sync.Mutex
+	out    io.Writer
+	rows   []*networkProgress
+	// drawn is the number of lines of the board currently on the terminal.
+	drawn int
+	done  chan struct{}
+}
+
+// networkProgress is the status of a network on a progress board. Its methods do nothing on a nil networkProgress,
+// so that networks resolved without a board can report their progress all the same.
+type networkProgress struct {
+	board *progressBoard
+	name  string
+	state string
+	// calls returns the number of RPC calls made so far, once the network is dialed.
+	calls     func() int
+	low, high int64
+	block     int64
+	started   time.Time
+	duration  time.Duration
+}
+
+// stderrIsTerminal returns whether stderr is an interactive terminal, which the progress board can be drawn on.
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// startProgressBoard draws a progress board for networks on stderr until stopped, writing log messages above it.
+func startProgressBoard(networks []Network) *progressBoard {
+	board := progressBoard{out: os.Stderr, done: make(chan struct{})}
+	for _, network := range networks {
+		board.rows = append(board.rows, &networkProgress{board: &board, name: network.Name, state: "waiting"})
+	}
+
+	activeBoard.Store(&board)
+
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-board.done:
+				return
+			case <-ticker.C:
+				board.locker.Lock()
+				board.redraw()
+				board.locker.Unlock()
+			}
+		}
+	}()
+
+	return &board
+}
+
+// stop draws the board a last time and leaves it on the terminal, writing log messages below it from then on.
+func (b *progressBoard) stop() {
+	if b == nil {
+		return
+	}
+
+	close(b.done)
+
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	b.redraw()
+	b.drawn = 0
+
+	activeBoard.CompareAndSwap(b, nil)
+}
+
+// track returns the row of network on b, or nil if b is nil or doesn't list it.
+func (b *progressBoard) track(network string) *networkProgress {
+	if b == nil {
+		return nil
+	}
+
+	for _, row := range b.rows {
+		if row.name == network {
+			return row
+		}
+	}
+
+	return nil
+}
+
+// writeAbove writes p to the terminal above the board.
+func (b *progressBoard) writeAbove(p []byte) (int, error) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	b.erase()
+
+	n, err := b.out.Write(p)
+
+	b.draw()
+
+	return n, err
+}
+
+// redraw replaces the board on the terminal with its current state.
+func (b *progressBoard) redraw() {
+	b.erase()
+	b.draw()
+}
+
+// erase moves the cursor back to the first line of the board and clears it and everything below.
+func (b *progressBoard) erase() {
+	if b.drawn > 0 {
+		fmt.Fprintf(b.out, "\033[%dA\033[J", b.drawn)
+		b.drawn = 0
+	}
+}
+
+// draw writes the board below the cursor.
+func (b *progressBoard) draw() {
+	width := 0
+	for _, row := range b.rows {
+		width = max(width, len(row.name))
+	}
+
+	var board strings.Builder
+	for _, row := range b.rows {
+		fmt.Fprintf(&board, "%-*s  %s\n", width, row.name, row.describe())
+	}
+
+	_, _ = io.WriteString(b.out, board.String())
+	b.drawn = len(b.rows)
+}
+
+// describe returns the status of p as a line of the board. The board must be locked.
+func (p *networkProgress) describe() string {
+	var columns []string
+
+	switch p.state {
+	case "searching":
+		if p.high > 0 {
+			columns = append(columns, fmt.Sprintf("searching blocks %d-%d", p.low, p.high))
+		} else {
+			columns = append(columns, p.state)
+		}
+	case "done":
+		columns = append(columns, fmt.Sprintf("done at block %d", p.block))
+	default:
+		columns = append(columns, p.state)
+	}
+
+	if p.calls != nil {
+		columns = append(columns, fmt.Sprintf("%d calls", p.calls()))
+	}
+
+	switch {
+	case p.duration > 0:
+		columns = append(columns, p.duration.Round(time.Millisecond).String())
+	case !p.started.IsZero():
+		columns = append(columns, time.Since(p.started).Round(100*time.Millisecond).String())
+	}
+
+	return strings.Join(columns, "  ")
+}
+
+// set updates p under the lock of its board.
+func (p *networkProgress) set(update func(p *networkProgress)) {
+	if p == nil {
+		return
+	}
+
+	p.board.locker.Lock()
+	defer p.board.locker.Unlock()
+
+	update(p)
+}
+
+// dialing marks the network as connecting to its endpoints.
+func (p *networkProgress) dialing() {
+	p.set(func(p *networkProgress) {
+		p.state, p.started = "dialing", time.Now()
+	})
+}
+
+// searching marks the network as searching for its block, counting the calls of conn.
+func (p *networkProgress) searching(calls func() int) {
+	p.set(func(p *networkProgress) {
+		p.state, p.calls = "searching", calls
+	})
+}
+
+// narrowed records the range of blocks the search of the network is down to.
+func (p *networkProgress) narrowed(low, high int64) {
+	p.set(func(p *networkProgress) {
+		p.low, p.high = low, high
+	})
+}
+
+// finished marks the network as resolved, or failed if result has an error.
+func (p *networkProgress) finished(result resolution) {
+	p.set(func(p *networkProgress) {
+		p.state, p.block, p.duration = "done", result.Block, max(result.Duration, time.Millisecond)
+		switch {
+		case result.notConfigured():
+			p.state = "not configured"
+		case result.Err != nil:
+			p.state = "failed"
+		}
+
+		// The connection is closed, so its count is frozen
+		calls := result.RPCCalls
+		p.calls = func() int { return calls }
+	})
+}
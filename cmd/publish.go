@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/arweave"
+	"get-node-start-block/pkg/ipfs"
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Pin the config to IPFS, and optionally store it on Arweave, printing where nodes can fetch it",
+	Long: `Pin the config to IPFS through the RPC API of an IPFS node, and store it on Arweave too when given a wallet
+to pay for the upload. The IPFS CID and the Arweave transaction ID are printed as ipfs:// and ar:// URLs.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		skipIPFS, _ := cmd.Flags().GetBool("no-ipfs")
+		ipfsAPI, _ := cmd.Flags().GetString("ipfs-api")
+		walletPath, _ := cmd.Flags().GetString("arweave-wallet")
+		gateway, _ := cmd.Flags().GetString("arweave-gateway")
+
+		if path == "" {
+			path = configPath
+		}
+
+		if walletPath == "" {
+			walletPath = os.Getenv("ARWEAVE_WALLET")
+		}
+
+		if skipIPFS && walletPath == "" {
+			return fmt.Errorf("nothing to publish to: --no-ipfs needs an --arweave-wallet")
+		}
+
+		// The config is parsed first, so that a broken file is never published
+		if _, err := loadConfig(path); err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		if !skipIPFS {
+			if ipfsAPI == "" {
+				ipfsAPI = os.Getenv("IPFS_API_URL")
+			}
+
+			cid, err := ipfs.NewClient(ipfsAPI, os.Getenv("IPFS_API_AUTH")).Add(cmd.Context(), filepath.Base(path), content)
+			if err != nil {
+				return fmt.Errorf("error publishing to IPFS: %w", err)
+			}
+
+			slog.Info("Config pinned to IPFS", "path", path, "cid", cid)
+			fmt.Printf("ipfs://%s\n", cid)
+		}
+
+		if walletPath != "" {
+			wallet, err := arweave.LoadWallet(walletPath)
+			if err != nil {
+				return err
+			}
+
+			tags := []arweave.Tag{
+				{Name: "Content-Type", Value: "application/json"},
+				{Name: "App-Name", Value: rootCmd.Use},
+			}
+
+			id, err := arweave.NewClient(gateway).Upload(cmd.Context(), wallet, content, tags)
+			if err != nil {
+				return fmt.Errorf("error publishing to Arweave: %w", err)
+			}
+
+			slog.Info("Config uploaded to Arweave, available once the transaction is mined", "path", path, "transaction", id)
+			fmt.Printf("ar://%s\n", id)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	publishCmd.Flags().String("file", "", "path of the config to publish, e.g. the --output of a resolve run (defaults to --config)")
+	publishCmd.Flags().Bool("no-ipfs", false, "skip IPFS, only storing the config on Arweave")
+	publishCmd.Flags().String("ipfs-api", "", "URL of the RPC API of the IPFS node pinning the config (defaults to IPFS_API_URL, or a local node)")
+	publishCmd.Flags().String("arweave-wallet", "", "path of the JWK wallet paying for storing the config on Arweave (defaults to ARWEAVE_WALLET, Arweave is skipped if neither is set)")
+	publishCmd.Flags().String("arweave-gateway", "https://arweave.net", "Arweave gateway to post the transaction to")
+
+	rootCmd.AddCommand(publishCmd)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/nodeadmin"
+)
+
+const (
+	// nodeAdminTokenEnv holds the token of the admin API of the nodes when --token is not given, since tokens are
+	// secrets better kept out of command lines.
+	nodeAdminTokenEnv = "NODE_ADMIN_TOKEN"
+	// pushTimeout bounds the time spent pushing the params to a single node.
+	pushTimeout = 30 * time.Second
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push the start blocks of the config to running RSS3 Nodes through their admin API",
+	Long: `Push the start blocks of the config, along with the block times and genesis and Farcaster timestamps, to the
+admin config endpoint of running RSS3 Nodes, which apply them without their config files being edited. Every node
+is pushed to even when others fail, and the command fails if any of them did.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		nodeURLs, _ := cmd.Flags().GetStringSlice("node-url")
+		adminPath, _ := cmd.Flags().GetString("admin-path")
+		token, _ := cmd.Flags().GetString("token")
+
+		if len(nodeURLs) == 0 {
+			return fmt.Errorf("no node to push to: --node-url is required")
+		}
+
+		if path == "" {
+			path = configPath
+		}
+
+		if token == "" {
+			token = os.Getenv(nodeAdminTokenEnv)
+		}
+
+		config, err := loadConfig(path)
+		if err != nil {
+			return err
+		}
+
+		params := nodeadmin.Params{
+			NetworkStartBlock:         config.StartBlockNumbers(),
+			NetworkBlockTime:          config.NetworkBlockTime,
+			NetworkGenesisTimestamp:   config.NetworkGenesisTimestamp,
+			NetworkFarcasterTimestamp: config.NetworkFarcasterTimestamp,
+		}
+
+		var errs []error
+
+		for _, nodeURL := range nodeURLs {
+			if err := pushParams(cmd.Context(), nodeadmin.NewClient(nodeURL, adminPath, token), params); err != nil {
+				slog.Error("Error pushing start blocks", "node", nodeURL, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", nodeURL, err))
+
+				continue
+			}
+
+			slog.Info("Start blocks pushed", "node", nodeURL, "networks", len(params.NetworkStartBlock))
+		}
+
+		return errors.Join(errs...)
+	},
+}
+
+// pushParams pushes params to the node of client, giving up after pushTimeout.
+func pushParams(ctx context.Context, client *nodeadmin.Client, params nodeadmin.Params) error {
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+
+	return client.UpdateParams(ctx, params)
+}
+
+func init() {
+	pushCmd.Flags().String("file", "", "path of the config to push, e.g. the --output of a resolve run (defaults to --config)")
+	pushCmd.Flags().StringSlice("node-url", nil, "URL of the RSS3 Node to push to, like http://node:8080 (repeatable)")
+	pushCmd.Flags().String("admin-path", nodeadmin.DefaultPath, "path of the config endpoint of the admin API of the nodes")
+	pushCmd.Flags().String("token", "", "bearer token of the admin API of the nodes (defaults to "+nodeAdminTokenEnv+")")
+
+	rootCmd.AddCommand(pushCmd)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/blockfinder"
+)
+
+var rangeCmd = &cobra.Command{
+	Use:   "range",
+	Short: "Find the start and end blocks of every network for a time window, e.g. an epoch to backfill",
+	Long: `Resolve both boundaries of the window from --from to --to on every network. The start block is picked around
+--from in --direction, like resolve does, and the end block is the last block before --to, so that the range
+covers the window without reaching into the next one, which starts at --to.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromFlag, _ := cmd.Flags().GetString("from")
+		toFlag, _ := cmd.Flags().GetString("to")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		from, err := parseTimestamp(fromFlag)
+		if err != nil {
+			return fmt.Errorf("error parsing --from: %w", err)
+		}
+
+		to, err := parseTimestamp(toFlag)
+		if err != nil {
+			return fmt.Errorf("error parsing --to: %w", err)
+		}
+
+		if to <= from {
+			return fmt.Errorf("invalid window: --to must be after --from")
+		}
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		options, err := resolveOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer options.Close()
+
+		networkList, err := selectedNetworks(cmd, config)
+		if err != nil {
+			return err
+		}
+
+		// The window is the same for every network, so networks don't override it
+		for index := range networkList {
+			networkList[index].Target = ""
+		}
+		options.FromGenesis = false
+
+		// Aligning to the start of epochs only applies to start blocks, so end blocks stay where the search picked them
+		endOptions := options
+		endOptions.Direction, endOptions.AlignEpochs = blockfinder.Before, false
+
+		slog.Info("Resolving block ranges",
+			"from", time.Unix(from, 0).UTC().Format(time.RFC3339), "to", time.Unix(to, 0).UTC().Format(time.RFC3339))
+
+		starts := resolveAll(cmd.Context(), networkList, from, options)
+		// A block stamped at --to belongs to the next window, so the end block is the last one before it
+		ends := resolveAll(cmd.Context(), networkList, to-1, endOptions)
+
+		table := rangeTable{From: from, To: to, NetworkBlockRange: make(map[string]blockRange)}
+
+		var failed int
+
+		for index, network := range networkList {
+			logger := slog.With("network", network.Name)
+
+			start, end := starts[index], ends[index]
+
+			// Both boundaries of networks without endpoints are left out the same way
+			if start.notConfigured() {
+				logger.Warn("Network not configured, leaving it out", "reason", start.Err)
+				continue
+			}
+
+			err := start.Err
+			if err == nil {
+				err = end.Err
+			}
+
+			if err == nil && end.Block < start.Block {
+				err = fmt.Errorf("no blocks in the window: the last block before it is %d", end.Block)
+			}
+
+			if err != nil {
+				logger.Error("Error resolving block range", "error", err)
+				failed++
+
+				continue
+			}
+
+			table.NetworkBlockRange[network.Name] = blockRange{StartBlock: start.Block, EndBlock: end.Block}
+
+			logger.Info("Resolved block range", "start_block", start.Block, "end_block", end.Block)
+		}
+
+		printRangeTable(networkList, table)
+
+		if outputPath != "" {
+			content, err := json.MarshalIndent(table, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error marshaling block ranges: %w", err)
+			}
+
+			if err := writeFile(outputPath, content); err != nil {
+				return err
+			}
+
+			slog.Info("Block ranges written", "path", outputPath)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to resolve the block range of %d networks", failed)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rangeCmd.Flags().String("from", "", "start of the window as Unix seconds, RFC3339 date (e.g. 2024-06-01T00:00:00Z) or time before now (e.g. now-30d)")
+	rangeCmd.Flags().String("to", "", "end of the window, in the same formats as --from")
+	rangeCmd.Flags().String("output", "", "path to write the block ranges to as JSON")
+	_ = rangeCmd.MarkFlagRequired("from")
+	_ = rangeCmd.MarkFlagRequired("to")
+	addResolveFlags(rangeCmd)
+	addSelectFlags(rangeCmd)
+
+	rootCmd.AddCommand(rangeCmd)
+}
+
+// rangeTable maps every network to the range of blocks of a time window.
+type rangeTable struct {
+	From              int64                 `json:"from"`
+	To                int64                 `json:"to"`
+	NetworkBlockRange map[string]blockRange `json:"network_block_range"`
+}
+
+// blockRange is the first and last block of a network in a time window, both included.
+type blockRange struct {
+	StartBlock int64 `json:"start_block"`
+	EndBlock   int64 `json:"end_block"`
+}
+
+// printRangeTable prints table with a row per network, leaving the blocks of unresolved networks empty.
+func printRangeTable(networkList []Network, table rangeTable) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "NETWORK\tSTART BLOCK\tEND BLOCK")
+
+	for _, network := range networkList {
+		row := []string{network.Name, "-", "-"}
+
+		if blocks, ok := table.NetworkBlockRange[network.Name]; ok {
+			row[1], row[2] = strconv.FormatInt(blocks.StartBlock, 10), strconv.FormatInt(blocks.EndBlock, 10)
+		}
+
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+
+	writer.Flush()
+}
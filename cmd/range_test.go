@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"get-node-start-block/pkg/mockchain"
+)
+
+func TestRangeWindowBoundaries(t *testing.T) {
+	// A block every 12 seconds, so blocks 100 and 200 land exactly on the boundaries of the windows
+	chain := mockchain.Chain{ChainID: 1, GenesisTime: 1_600_000_000, BlockTime: 12, Height: 10_000}
+
+	fixturesPath := writeFixtures(t, map[string]mockchain.Chain{"test": chain})
+	configPath := writeJSON(t, "config.json", map[string]interface{}{
+		"networks": []map[string]interface{}{{"name": "test", "type": "ethereum"}},
+	})
+
+	boundaries := []int64{chain.Timestamp(100), chain.Timestamp(200), chain.Timestamp(300)}
+
+	var ranges []blockRange
+
+	for index := 0; index+1 < len(boundaries); index++ {
+		outputPath := filepath.Join(t.TempDir(), "range.json")
+
+		err := runCommand(t, "range", "--config", configPath, "--fixtures="+fixturesPath, "--no-cache", "--lookups", "none",
+			"--from", strconv.FormatInt(boundaries[index], 10), "--to", strconv.FormatInt(boundaries[index+1], 10),
+			"--output", outputPath)
+		if err != nil {
+			t.Fatalf("range error = %v", err)
+		}
+
+		var table rangeTable
+		readJSON(t, outputPath, &table)
+
+		ranges = append(ranges, table.NetworkBlockRange["test"])
+	}
+
+	want := []blockRange{{StartBlock: 100, EndBlock: 199}, {StartBlock: 200, EndBlock: 299}}
+	for index := range want {
+		if ranges[index] != want[index] {
+			t.Errorf("window %d = %+v, want %+v", index, ranges[index], want[index])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// report is the machine-readable summary of a resolve run written by --report.
+type report struct {
+	Target     int64     `json:"target"`
+	Direction  string    `json:"direction"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Succeeded  int       `json:"succeeded"`
+	Failed     int       `json:"failed"`
+	// NotConfigured is the number of networks left out for lack of endpoints, which count as neither.
+	NotConfigured int             `json:"not_configured"`
+	Networks      []networkReport `json:"networks"`
+}
+
+// networkReport is the outcome of resolving a single network in a report.
+type networkReport struct {
+	Network string `json:"network"`
+	Type    string `json:"type"`
+	// Block, BlockTimestamp and Difference are only set for networks that were resolved.
+	Block          *int64 `json:"block,omitempty"`
+	BlockTimestamp *int64 `json:"block_timestamp,omitempty"`
+	// Estimated is set for blocks estimated offline, which are approximations.
+	Estimated bool `json:"estimated,omitempty"`
+	// NotConfigured is set for networks left out for lack of endpoints, as opposed to failed ones.
+	NotConfigured bool `json:"not_configured,omitempty"`
+	// Target is set for networks resolved for a target of their own instead of the target of the run.
+	Target *int64 `json:"target,omitempty"`
+	// Difference is the number of seconds between the block and the target, negative for blocks before it.
+	Difference *int64 `json:"difference,omitempty"`
+	// BlockTime is the average number of seconds between blocks around the block, if it was measured.
+	BlockTime *float64 `json:"block_time,omitempty"`
+	RPCCalls  int      `json:"rpc_calls"`
+	// DurationMS is the number of milliseconds spent resolving the network.
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// newReport summarizes the results of resolving networks for targetTimestamp.
+func newReport(targetTimestamp int64, direction string, startedAt time.Time, results []resolution) *report {
+	runReport := report{
+		Target:     targetTimestamp,
+		Direction:  direction,
+		StartedAt:  startedAt.UTC(),
+		FinishedAt: time.Now().UTC(),
+		Networks:   make([]networkReport, 0, len(results)),
+	}
+
+	for _, result := range results {
+		networkReport := networkReport{
+			Network:    result.Network.Name,
+			Type:       result.Network.Type,
+			RPCCalls:   result.RPCCalls,
+			DurationMS: result.Duration.Milliseconds(),
+		}
+
+		if result.Target != targetTimestamp {
+			target := result.Target
+			networkReport.Target = &target
+		}
+
+		switch {
+		case result.notConfigured():
+			networkReport.Error = result.Err.Error()
+			networkReport.NotConfigured = true
+			runReport.NotConfigured++
+		case result.Err != nil:
+			networkReport.Error = result.Err.Error()
+			runReport.Failed++
+		default:
+			block, blockTimestamp, difference := result.Block, result.BlockTimestamp, result.BlockTimestamp-result.Target
+
+			networkReport.Block = &block
+			networkReport.BlockTimestamp = &blockTimestamp
+			networkReport.Difference = &difference
+			networkReport.Estimated = result.Estimated
+
+			if result.BlockTime > 0 {
+				blockTime := result.BlockTime
+				networkReport.BlockTime = &blockTime
+			}
+			runReport.Succeeded++
+		}
+
+		runReport.Networks = append(runReport.Networks, networkReport)
+	}
+
+	return &runReport
+}
+
+// writeReport writes runReport to path as JSON.
+func writeReport(path string, runReport *report) error {
+	content, err := json.MarshalIndent(runReport, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling report: %w", err)
+	}
+
+	return writeFile(path, content)
+}
+
+// reportWriters write the outcome of a run as a table, by output format, rather than the config. Tables have a row
+// per network with its block, the time of the block and its difference from the target, for pasting into
+// governance posts and release notes.
+var reportWriters = map[string]func(path string, runReport *report) error{
+	"csv": writeCSVReport,
+	"md":  writeMarkdownReport,
+}
+
+// writeCSVReport writes runReport to path as a CSV table, with the time of blocks in RFC 3339 and their difference
+// from the target in seconds.
+func writeCSVReport(path string, runReport *report) error {
+	var buffer bytes.Buffer
+
+	writer := csv.NewWriter(&buffer)
+	rows := [][]string{{"network", "block", "block_time", "difference", "error"}}
+
+	for _, network := range runReport.Networks {
+		row := []string{network.Network, "", "", "", network.Error}
+		if network.Block != nil {
+			row[1] = strconv.FormatInt(*network.Block, 10)
+			row[2] = time.Unix(*network.BlockTimestamp, 0).UTC().Format(time.RFC3339)
+			row[3] = strconv.FormatInt(*network.Difference, 10)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("error writing CSV report: %w", err)
+	}
+
+	return replaceFile(path, buffer.Bytes())
+}
+
+// writeMarkdownReport writes runReport to path as a Markdown table, under a line naming the target.
+func writeMarkdownReport(path string, runReport *report) error {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Start blocks for `%d` (%s).\n\n", runReport.Target, time.Unix(runReport.Target, 0).UTC().Format(time.RFC3339))
+
+	builder.WriteString("| Network | Block | Block time | Difference |\n")
+	builder.WriteString("| --- | ---: | --- | ---: |\n")
+
+	for _, network := range runReport.Networks {
+		if network.NotConfigured {
+			fmt.Fprintf(&builder, "| %s | | | not configured |\n", network.Network)
+			continue
+		}
+
+		if network.Block == nil {
+			fmt.Fprintf(&builder, "| %s | | | failed: %s |\n", network.Network, strings.ReplaceAll(network.Error, "|", `\|`))
+			continue
+		}
+
+		block := strconv.FormatInt(*network.Block, 10)
+		if network.Estimated {
+			block += " (estimated)"
+		}
+
+		fmt.Fprintf(&builder, "| %s | %s | %s | %s |\n", network.Network, block,
+			time.Unix(*network.BlockTimestamp, 0).UTC().Format(time.RFC3339), time.Duration(*network.Difference)*time.Second)
+	}
+
+	return replaceFile(path, []byte(builder.String()))
+}
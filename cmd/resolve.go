@@ -0,0 +1,1312 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"get-node-start-block/pkg/blockcache"
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+	"get-node-start-block/pkg/github"
+	"get-node-start-block/pkg/history"
+	"get-node-start-block/pkg/provider"
+	"get-node-start-block/pkg/rediscache"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Find the start block of every network for a target timestamp and update the config",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timestampFlag, _ := cmd.Flags().GetString("timestamp")
+		format, _ := cmd.Flags().GetString("format")
+		compat, _ := cmd.Flags().GetBool("compat")
+		configMapName, _ := cmd.Flags().GetString("configmap-name")
+		configMapNamespace, _ := cmd.Flags().GetString("configmap-namespace")
+		helmKey, _ := cmd.Flags().GetString("helm-key")
+		outputPath, _ := cmd.Flags().GetString("output")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		reportPath, _ := cmd.Flags().GetString("report")
+		metricsAddress, _ := cmd.Flags().GetString("metrics-addr")
+		daemon, _ := cmd.Flags().GetBool("daemon")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		watch, _ := cmd.Flags().GetBool("watch")
+		timestampFile, _ := cmd.Flags().GetString("timestamp-file")
+		githubPR, _ := cmd.Flags().GetBool("github-pr")
+		uploadFlag, _ := cmd.Flags().GetString("upload")
+		kvFlags, _ := cmd.Flags().GetStringSlice("kv")
+		historyURL, _ := cmd.Flags().GetString("history")
+		if historyURL == "" {
+			historyURL = os.Getenv(historyEnv)
+		}
+		signKeyPath, _ := cmd.Flags().GetString("sign-key")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		failOnError, _ := cmd.Flags().GetBool("fail-on-error")
+		minSuccessFlag, _ := cmd.Flags().GetString("min-success")
+		maxChange, _ := cmd.Flags().GetDuration("max-change")
+		force, _ := cmd.Flags().GetBool("force")
+		offline, _ := cmd.Flags().GetBool("offline")
+		checkpointPath, _ := cmd.Flags().GetString("checkpoint")
+		resume, _ := cmd.Flags().GetBool("resume")
+		fromDeployment, _ := cmd.Flags().GetStringSlice("from-deployment")
+		webhooks, _ := cmd.Flags().GetStringSlice("webhook")
+		if len(webhooks) == 0 {
+			webhooks = endpointsFromEnv(webhooksEnv)
+		}
+
+		_, configFormat := configWriters[format]
+		if _, reportFormat := reportWriters[format]; !configFormat && !reportFormat {
+			return fmt.Errorf("invalid format %q: must be json, yaml, toml, env, tfvars, k8s-configmap, helm-values, csv or md", format)
+		}
+
+		// The config is read back by the next run, so formats it can't be read from are written next to it
+		if standaloneFormats[format] && outputPath == "" {
+			return fmt.Errorf("--format %s needs an --output path, as the config file stays JSON", format)
+		}
+
+		if slices.Contains(strings.Split(helmKey, "."), "") {
+			return fmt.Errorf("invalid --helm-key %q: must be a dot-separated path of keys", helmKey)
+		}
+
+		skip, require, err := selectFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		minSuccess, err := parseMinSuccess(minSuccessFlag)
+		if err != nil {
+			return err
+		}
+
+		if maxChange < 0 {
+			return fmt.Errorf("invalid maximum change %s: must not be negative", maxChange)
+		}
+
+		if force {
+			maxChange = 0
+		}
+
+		if daemon && watch {
+			return fmt.Errorf("--daemon and --watch can't be used together")
+		}
+
+		if resume && (daemon || watch || offline) {
+			return fmt.Errorf("--resume can't be used with --daemon, --watch or --offline")
+		}
+
+		if resume && checkpointPath == "" {
+			return fmt.Errorf("--resume needs a --checkpoint file")
+		}
+
+		if daemon && interval <= 0 {
+			return fmt.Errorf("invalid interval %s: must be positive", interval)
+		}
+
+		if outputPath == "" {
+			outputPath = configPath
+		}
+
+		run := resolveRun{
+			Timestamp:     timestampFlag,
+			TimestampFile: timestampFile,
+			Format:        format,
+			Compat:        compat,
+			Output: outputOptions{
+				Compat:             compat,
+				ConfigMapName:      configMapName,
+				ConfigMapNamespace: configMapNamespace,
+				HelmKey:            helmKey,
+			},
+			OutputPath:   outputPath,
+			DryRun:       dryRun,
+			ReportPath:   reportPath,
+			PrintChanges: watch,
+			Webhooks:     webhooks,
+			Policy:       failurePolicy{FailOnError: failOnError, MinSuccess: minSuccess},
+			MaxChange:    maxChange,
+			Offline:      offline,
+			Checkpoint:   checkpointPath,
+			Resume:       resume,
+			Skip:         skip,
+			Require:      require,
+
+			FromDeployment: fromDeployment,
+
+			// JSON logs are for machines, which have no use for the board
+			Progress: !quiet && logFormat == "text" && stderrIsTerminal(),
+		}
+
+		if githubPR {
+			if dryRun {
+				return fmt.Errorf("--github-pr can't be used with --dry-run")
+			}
+
+			proposal, err := githubOptionsFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			run.GitHub = &proposal
+		}
+
+		if uploadFlag != "" {
+			if dryRun {
+				return fmt.Errorf("--upload can't be used with --dry-run")
+			}
+
+			destination, err := newUploadDestination(cmd.Context(), uploadFlag)
+			if err != nil {
+				return err
+			}
+
+			run.Upload = destination
+		}
+
+		if len(kvFlags) > 0 && dryRun {
+			return fmt.Errorf("--kv can't be used with --dry-run")
+		}
+
+		for _, kvFlag := range kvFlags {
+			destination, err := newKVDestination(kvFlag)
+			if err != nil {
+				return err
+			}
+
+			run.KV = append(run.KV, destination)
+		}
+
+		if signKeyPath != "" {
+			if format != "json" {
+				return fmt.Errorf("--sign-key only signs JSON configs")
+			}
+
+			key, err := loadSigningKey(signKeyPath)
+			if err != nil {
+				return err
+			}
+
+			run.SigningKey = key
+		}
+
+		// Relative targets are parsed again by every run, but mistakes should fail before the first one
+		if _, err := run.targetTimestamp(); err != nil && !watch {
+			return err
+		}
+
+		options, err := resolveOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer options.Close()
+
+		// Dry runs write no params, so there is nothing to audit later
+		if historyURL != "" && !dryRun {
+			db, err := history.Open(cmd.Context(), historyURL)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			run.History = db
+		}
+
+		if offline && options.FromGenesis {
+			return fmt.Errorf("--offline can't be used with --from-genesis")
+		}
+
+		if metricsAddress != "" {
+			if err := serveMetrics(cmd.Context(), metricsAddress); err != nil {
+				return fmt.Errorf("error serving metrics: %w", err)
+			}
+		}
+
+		run.Options = options
+
+		switch {
+		case daemon:
+			return runDaemon(cmd.Context(), interval, run.execute)
+		case watch:
+			paths := []string{configPath, dotenvPath}
+			if timestampFile != "" {
+				paths = append(paths, timestampFile)
+			}
+
+			return runWatch(cmd.Context(), paths, run.execute)
+		default:
+			return run.execute(cmd.Context())
+		}
+	},
+}
+
+// githubOptionsFromFlags returns the options of --github-pr set by the flags of cmd.
+func githubOptionsFromFlags(cmd *cobra.Command) (githubOptions, error) {
+	repositoryFlag, _ := cmd.Flags().GetString("github-repo")
+	base, _ := cmd.Flags().GetString("github-base")
+	path, _ := cmd.Flags().GetString("github-path")
+
+	repository, err := github.ParseRepository(repositoryFlag)
+	if err != nil {
+		return githubOptions{}, err
+	}
+
+	token := os.Getenv(githubTokenEnv)
+	if token == "" {
+		return githubOptions{}, fmt.Errorf("--github-pr needs a GitHub token in %s", githubTokenEnv)
+	}
+
+	return githubOptions{Repository: repository, Base: base, Path: path, Token: token, APIURL: os.Getenv(githubAPIURLEnv)}, nil
+}
+
+// resolveRun is a single run of the resolve command, resolving every network and writing the config.
+type resolveRun struct {
+	// Timestamp is the target as given to --timestamp, which may be relative to the time of the run.
+	Timestamp string
+	// TimestampFile is the path of a file holding the target, read by every run instead of Timestamp if set.
+	TimestampFile string
+	Format        string
+	// Compat writes JSON configs in the flat layout of version 1, for consumers that don't read later versions.
+	Compat bool
+	// Output controls how the config is written in Format.
+	Output     outputOptions
+	OutputPath string
+	DryRun     bool
+	ReportPath string
+	// PrintChanges prints the changes to the start blocks after writing the config, as dry runs do.
+	PrintChanges bool
+	// GitHub, if set, proposes the written config in a pull request.
+	GitHub *githubOptions
+	// Upload, if set, uploads the written config to object storage.
+	Upload *uploadDestination
+	// KV are the key-value stores the params of the written config are written to, for fleets of nodes to watch.
+	KV []*kvDestination
+	// History, if set, records every run with the resolution of every network.
+	History *history.DB
+	// Offline estimates start blocks from reference blocks instead of resolving them, without any request.
+	Offline bool
+	// Checkpoint is the path of the file the progress of the run is saved to as networks are resolved,
+	// or empty not to save it.
+	Checkpoint string
+	// Resume only resolves the networks the run saved to Checkpoint didn't resolve, if it was for the same target.
+	Resume bool
+	// MaxChange is the largest time start blocks may move from their previous value, or 0 for no limit.
+	MaxChange time.Duration
+	// Policy decides whether the config is written when some networks fail.
+	Policy failurePolicy
+	// Progress draws the live status of every network while resolving.
+	Progress bool
+	// SigningKey, if set, signs the written config, writing its detached signature next to it.
+	SigningKey *signingKey
+	// Webhooks are the Slack or Discord webhook URLs notified with a summary of every run.
+	Webhooks []string
+	// Skip are the networks left out of the run, keeping their start blocks as they were.
+	Skip []string
+	// Require are the networks that must be resolved for the config to be written.
+	Require []string
+	// FromDeployment are the networks started at the deployment of their contracts instead of the target.
+	FromDeployment []string
+	Options        resolveOptions
+}
+
+// target returns the target of the run as given, from its timestamp file if it has one.
+func (run resolveRun) target() (string, error) {
+	if run.TimestampFile == "" {
+		return run.Timestamp, nil
+	}
+
+	content, err := os.ReadFile(run.TimestampFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading timestamp file: %w", err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// targetTimestamp returns the target of the run, from its timestamp file if it has one.
+func (run resolveRun) targetTimestamp() (int64, error) {
+	value, err := run.target()
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp, err := parseTimestamp(value)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing target timestamp: %w", err)
+	}
+
+	return timestamp, nil
+}
+
+// execute resolves the start blocks of every network for the target of the run, and writes them to the config.
+func (run resolveRun) execute(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "resolve")
+
+	var summary runSummary
+
+	err := run.resolve(ctx, &summary)
+
+	span.SetAttributes(attribute.Int64("target", summary.Target), attribute.Int("networks", len(summary.Results)))
+	endSpan(span, err)
+
+	if len(run.Webhooks) > 0 {
+		notifyWebhooks(ctx, run.Webhooks, summary.message(err))
+	}
+
+	return err
+}
+
+// resolve does the work of execute, recording what it did in summary.
+func (run resolveRun) resolve(ctx context.Context, summary *runSummary) error {
+	startedAt := time.Now()
+	options := run.Options
+
+	target, err := run.target()
+	if err != nil {
+		return err
+	}
+
+	targetTimestamp, err := run.targetTimestamp()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Resolving start blocks", "target", targetTimestamp, "target_time", time.Unix(targetTimestamp, 0).UTC().Format(time.RFC3339))
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	previousStartBlocks := config.StartBlockNumbers()
+	for network, block := range previousStartBlocks {
+		slog.Debug("Start block from config", "network", network, "block", block)
+	}
+
+	networkList, err := selectNetworks(networks(config), run.Skip, run.Require)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range run.FromDeployment {
+		if _, err := lookupNetwork(config, name); err != nil {
+			return fmt.Errorf("invalid --from-deployment: %w", err)
+		}
+	}
+
+	for index := range networkList {
+		if slices.Contains(run.FromDeployment, networkList[index].Name) {
+			networkList[index].Target = deploymentTarget
+		}
+	}
+
+	var checkpoint *checkpoint
+	if !run.Offline {
+		checkpoint = newCheckpoint(run.Checkpoint, configPath, target, targetTimestamp, string(options.Direction), options.FromGenesis)
+	}
+
+	var resumed []resolution
+
+	if run.Resume {
+		if resumed, targetTimestamp, err = run.resume(checkpoint, networkList, targetTimestamp); err != nil {
+			return err
+		}
+	}
+
+	if run.MaxChange > 0 && !run.Offline {
+		options.Guard = &changeGuard{Previous: previousStartBlocks, MaxChange: run.MaxChange}
+	}
+
+	var results []resolution
+
+	if run.Offline {
+		slog.Warn("Estimating start blocks offline, they are approximations to resolve again once the endpoints are reachable")
+
+		results = estimateAll(networkList, targetTimestamp)
+	} else {
+		pending := unresolvedNetworks(networkList, resumed)
+
+		if run.Progress {
+			options.Progress = startProgressBoard(pending)
+		}
+
+		options.Checkpoint = checkpoint
+		results = mergeResults(networkList, resumed, resolveAll(ctx, pending, targetTimestamp, options))
+		options.Progress.stop()
+	}
+	recordMetrics(results)
+
+	summary.Target, summary.Results = targetTimestamp, results
+
+	// Networks resolved before an interruption are still written, so their work isn't lost
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		slog.Warn("Interrupted, keeping the start blocks resolved so far")
+	}
+
+	// Results are in network order, so the output and the merged config don't depend on scheduling
+	for _, result := range results {
+		logger := slog.With("network", result.Network.Name, "rpc_calls", result.RPCCalls, "duration", result.Duration)
+
+		if result.notConfigured() {
+			logger.Warn("Network not configured, leaving it out", "reason", result.Err)
+			continue
+		}
+
+		if result.Err != nil {
+			logger.Error("Error resolving network", "error", result.Err)
+
+			if previous, ok := previousStartBlocks[result.Network.Name]; ok {
+				logger.Warn("Keeping previous start block", "block", previous)
+			} else {
+				logger.Warn("Leaving network without a start block, as it has none yet")
+			}
+
+			continue
+		}
+
+		// Update config with new value
+		resolvedAt := time.Now().UTC().Truncate(time.Second)
+
+		startBlock := StartBlock{
+			Block:      result.Block,
+			Timestamp:  result.BlockTimestamp,
+			Source:     startSourceSearch,
+			ResolvedAt: &resolvedAt,
+			Provenance: result.provenance(),
+		}
+
+		switch {
+		case result.Estimated:
+			// Neither the block nor its timestamp were seen on the chain
+			startBlock.Source, startBlock.Timestamp, startBlock.Provenance = startSourceEstimate, 0, nil
+		case result.Genesis:
+			startBlock.Source = startSourceGenesis
+		case result.Deployment:
+			startBlock.Source = startSourceDeployment
+		}
+
+		config.NetworkStartBlock[result.Network.Name] = startBlock
+		if result.BlockTime > 0 {
+			config.SetBlockTime(result.Network.Name, result.BlockTime)
+		}
+		if result.Genesis {
+			config.SetGenesisTimestamp(result.Network.Name, result.Target)
+		}
+		// Farcaster start points are Unix timestamps, which workers reading Farcaster time would misread
+		if result.Network.Type == "farcaster" {
+			config.SetFarcasterTimestamp(result.Network.Name, result.Block)
+		}
+		if result.Estimated {
+			logger.Warn("Estimated start block", "block", result.Block, "estimated_time", time.Unix(result.BlockTimestamp, 0).UTC().Format(time.RFC3339))
+			continue
+		}
+
+		logger.Info("Updated start block",
+			"block", result.Block,
+			"block_time", time.Unix(result.BlockTimestamp, 0).UTC().Format(time.RFC3339),
+			"difference", time.Duration(result.BlockTimestamp-result.Target)*time.Second)
+	}
+
+	runReport := newReport(targetTimestamp, string(options.Direction), startedAt, results)
+
+	if run.ReportPath != "" {
+		if err := writeReport(run.ReportPath, runReport); err != nil {
+			return err
+		}
+
+		slog.Info("Report written", "path", run.ReportPath)
+	}
+
+	if run.History != nil {
+		if err := recordHistory(ctx, run.History, runReport, results); err != nil {
+			return err
+		}
+
+		slog.Debug("Run recorded in history")
+	}
+
+	if err := run.Policy.check(results); err != nil {
+		return err
+	}
+
+	// Once every network is resolved there is nothing left to resume
+	if !interrupted && partialFailure(results) == nil {
+		checkpoint.clear()
+	}
+
+	changes := diffStartBlocks(previousStartBlocks, config.StartBlockNumbers())
+	summary.Changes = changes
+
+	if run.DryRun {
+		fmt.Println("Dry run, config file not written. Changes to start blocks:")
+
+		if len(changes) == 0 {
+			fmt.Println("No differences.")
+		}
+
+		printChanges(changes)
+
+		return run.outcome(interrupted, results)
+	}
+
+	if writeTable, ok := reportWriters[run.Format]; ok {
+		err = writeTable(run.OutputPath, runReport)
+	} else {
+		err = configWriters[run.Format](run.OutputPath, config, run.Output)
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Config file updated successfully", "path", run.OutputPath)
+
+	if run.SigningKey != nil {
+		if err := signConfig(run.OutputPath, run.SigningKey); err != nil {
+			return err
+		}
+
+		slog.Info("Config file signed", "signature", run.OutputPath+signatureSuffix)
+	}
+
+	if run.PrintChanges {
+		fmt.Println("Changes to start blocks:")
+
+		if len(changes) == 0 {
+			fmt.Println("No differences.")
+		}
+
+		printChanges(changes)
+	}
+
+	// An interrupted run publishes nothing, as its config is missing networks
+	if run.Upload != nil && !interrupted {
+		if err := uploadConfig(ctx, run.Upload, run.OutputPath, run.Format, targetTimestamp); err != nil {
+			return err
+		}
+	}
+
+	if !interrupted {
+		for _, destination := range run.KV {
+			if err := writeKV(ctx, destination, config); err != nil {
+				return fmt.Errorf("error writing params to %s: %w", destination.URL, err)
+			}
+		}
+	}
+
+	if run.GitHub != nil && !interrupted {
+		if err := proposeConfig(ctx, *run.GitHub, run.OutputPath, runReport); err != nil {
+			return err
+		}
+	}
+
+	return run.outcome(interrupted, results)
+}
+
+// resume loads the checkpoint file of the run, returning the results of the networks it resolved and the target
+// they were resolved for. A missing checkpoint, or one saved by a run for another config or target, resumes nothing.
+func (run resolveRun) resume(checkpoint *checkpoint, networks []Network, targetTimestamp int64) ([]resolution, int64, error) {
+	previous, err := loadCheckpoint(run.Checkpoint)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if previous == nil {
+		slog.Warn("No checkpoint to resume from, resolving every network", "path", run.Checkpoint)
+		return nil, targetTimestamp, nil
+	}
+
+	if reason := checkpoint.resumes(previous); reason != "" {
+		slog.Warn("Ignoring checkpoint, resolving every network", "path", run.Checkpoint, "reason", reason)
+		return nil, targetTimestamp, nil
+	}
+
+	resumed := checkpoint.resume(previous, networks)
+
+	slog.Info("Resuming from checkpoint", "path", run.Checkpoint, "resolved", len(resumed), "target", previous.Target, "saved_at", previous.UpdatedAt.Format(time.RFC3339))
+
+	return resumed, previous.Target, nil
+}
+
+// unresolvedNetworks returns the networks of networks without a result in resolved.
+func unresolvedNetworks(networks []Network, resolved []resolution) []Network {
+	done := make(map[string]bool, len(resolved))
+	for _, result := range resolved {
+		done[result.Network.Name] = true
+	}
+
+	var pending []Network
+	for _, network := range networks {
+		if !done[network.Name] {
+			pending = append(pending, network)
+		}
+	}
+
+	return pending
+}
+
+// mergeResults merges the results of resumed networks with the ones resolved by the run, in the order of networks.
+func mergeResults(networks []Network, resumed, resolved []resolution) []resolution {
+	byName := make(map[string]resolution, len(resumed)+len(resolved))
+	for _, result := range append(resumed, resolved...) {
+		byName[result.Network.Name] = result
+	}
+
+	results := make([]resolution, 0, len(networks))
+	for _, network := range networks {
+		results = append(results, byName[network.Name])
+	}
+
+	return results
+}
+
+// runDaemon runs run straight away and then every interval until ctx is done. Failed runs are logged
+// and retried at the next interval rather than stopping the daemon.
+func runDaemon(ctx context.Context, interval time.Duration, run func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := run(ctx); err != nil {
+			// A run cut short by a signal is the daemon stopping, not a failure
+			if ctx.Err() != nil {
+				return err
+			}
+
+			slog.Error("Error resolving start blocks, retrying at the next run", "error", err)
+		}
+
+		slog.Info("Waiting for the next run", "next_run", time.Now().Add(interval).UTC().Format(time.RFC3339))
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopping daemon")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// outcome returns the error the run ends with once its config is written: the interruption of interrupted runs,
+// or a partial failure if some networks failed.
+func (run resolveRun) outcome(interrupted bool, results []resolution) error {
+	if interrupted {
+		return interruption(interrupted, results)
+	}
+
+	return partialFailure(results)
+}
+
+// interruption returns the error of a run that was interrupted after resolving some of results, or nil if it wasn't.
+func interruption(interrupted bool, results []resolution) error {
+	if !interrupted {
+		return nil
+	}
+
+	var resolved int
+	for _, result := range results {
+		if result.Err == nil {
+			resolved++
+		}
+	}
+
+	return fmt.Errorf("interrupted after resolving %d of %d networks", resolved, len(results))
+}
+
+func init() {
+	resolveCmd.Flags().String("timestamp", defaultTimestamp, "target time as Unix seconds, RFC3339 date (e.g. 2024-06-01T00:00:00Z) or time before now (e.g. now-30d)")
+	resolveCmd.Flags().String("format", "json", "format of the written config: json, yaml to update the network_start_block section of an RSS3 Node config, toml or env to write the start blocks alone, like NODE_START_BLOCK_ETHEREUM=20000000 for systemd units and docker-compose, tfvars for Terraform (JSON if --output ends in .json), k8s-configmap or helm-values for CD pipelines, or csv or md for a table of the resolved blocks")
+	resolveCmd.Flags().String("configmap-name", "node-network-params", "name of the ConfigMap written by --format k8s-configmap")
+	resolveCmd.Flags().String("configmap-namespace", "", "namespace of the ConfigMap written by --format k8s-configmap (defaults to the namespace it is applied to)")
+	resolveCmd.Flags().String("helm-key", "config", "dot-separated path of the values --format helm-values nests the start blocks under, where the chart renders the node config from")
+	resolveCmd.Flags().Bool("compat", false, "write the JSON config in the flat layout of version 1, mapping networks to start block numbers, for consumers that don't read version 2")
+	resolveCmd.Flags().String("output", "", "path to write the updated config to (defaults to --config)")
+	resolveCmd.Flags().Bool("dry-run", false, "print the changes to the start blocks instead of writing the config")
+	resolveCmd.Flags().Bool("quiet", false, "don't draw the live status of every network on the terminal, only log, as when stderr isn't a terminal")
+	resolveCmd.Flags().StringSlice("from-deployment", nil, "networks to start at the deployment of the contracts their worker indexes instead of the target, like --from-deployment crossbell for its Character and LinkList contracts, or the anchor_contract of the network")
+	resolveCmd.Flags().Bool("fail-on-error", false, "don't write the config if any network fails")
+	resolveCmd.Flags().String("min-success", "", "share of networks that must be resolved for the config to be written, like 90% (defaults to any)")
+	resolveCmd.Flags().Duration("max-change", 0, "largest time a start block may move from its value in the config, beyond which the network fails, like 2400h to catch mistyped targets while allowing the quarterly epoch update (0 for no limit)")
+	resolveCmd.Flags().Bool("force", false, "allow start blocks to move by any amount, ignoring --max-change")
+	resolveCmd.Flags().Bool("offline", false, "estimate the start blocks from reference blocks and average block times without any request, for when the endpoints are unreachable")
+	resolveCmd.Flags().String("checkpoint", defaultCheckpointPath(), "path of the file the progress of the run is saved to as networks are resolved, for --resume (disabled if empty)")
+	resolveCmd.Flags().Bool("resume", false, "only resolve the networks the interrupted or failed run saved to --checkpoint didn't resolve, for the same config and target")
+	resolveCmd.Flags().String("report", "", "path to write a JSON report of the run to, with the outcome of every network")
+	resolveCmd.Flags().Bool("daemon", false, "keep running, resolving the start blocks again every --interval, e.g. for a rolling --timestamp like now-30d")
+	resolveCmd.Flags().Duration("interval", 24*time.Hour, "time between runs in daemon mode")
+	resolveCmd.Flags().Bool("watch", false, "keep running, resolving the start blocks again whenever the config, .env or --timestamp-file changes")
+	resolveCmd.Flags().String("timestamp-file", "", "path of a file holding the target time, read by every run instead of --timestamp")
+	resolveCmd.Flags().Bool("github-pr", false, "commit the written config to a new branch of --github-repo and open a pull request with the run report, using the token in "+githubTokenEnv)
+	resolveCmd.Flags().String("github-repo", "RSS3-Network/Node-NetworkParams-Script", "repository to open the pull request of --github-pr against, as owner/name")
+	resolveCmd.Flags().String("github-base", "", "branch to open the pull request of --github-pr against (defaults to the default branch)")
+	resolveCmd.Flags().String("github-path", "", "path of the config in the repository of --github-pr (defaults to --output)")
+	resolveCmd.Flags().String("history", "", "database to record every run and the resolution of every network in, a postgres:// URL or the path of an SQLite file (defaults to "+historyEnv+")")
+	resolveCmd.Flags().StringSlice("kv", nil, "etcd or Consul location to write the params to for nodes to watch, like etcd://127.0.0.1:2379/rss3/params or consul://127.0.0.1:8500/rss3/params (consul+https:// for TLS), with a key per network under networks/ and all of them under params (repeatable)")
+	resolveCmd.Flags().String("upload", "", "object storage location to upload the written config to, like s3://bucket/params or gs://bucket/params, under both <target>/ and latest/")
+	resolveCmd.Flags().String("sign-key", "", "path of an ed25519 PEM key or hex-encoded Ethereum key to sign the written config with, writing the signature to the config path with .sig appended")
+	resolveCmd.Flags().StringSlice("webhook", nil, "Slack or Discord webhook URLs to notify with a summary of every run, including failed ones (defaults to the comma-separated URLs in "+webhooksEnv+")")
+	resolveCmd.Flags().String("metrics-addr", "", "address to serve Prometheus metrics on at /metrics while running, like :9090 (disabled if empty)")
+	addResolveFlags(resolveCmd)
+	addSelectFlags(resolveCmd)
+
+	rootCmd.AddCommand(resolveCmd)
+}
+
+// resolution is the outcome of resolving the start block of a network.
+type resolution struct {
+	Network Network
+	// Target is the timestamp the network was resolved for, which is the genesis time of networks targeting
+	// their genesis.
+	Target int64
+	// Genesis is set for networks started at their genesis rather than searched for a target.
+	Genesis bool
+	// Deployment is set for networks started at the deployment of their contracts rather than searched for a target.
+	Deployment bool
+	// Estimated is set for blocks estimated offline rather than resolved, which are approximations.
+	Estimated      bool
+	Block          int64
+	BlockTimestamp int64
+	// BlockTime is the average number of seconds between blocks around Block, or 0 if it wasn't measured.
+	BlockTime float64
+	RPCCalls  int
+	// Endpoints is the health of the endpoints of the network, for connections reporting it.
+	Endpoints []endpoint.Health
+	Duration  time.Duration
+	Err       error
+}
+
+// notConfigured reports whether the network was left out of the run for lack of endpoints, which only fails
+// required networks.
+func (r resolution) notConfigured() bool {
+	return !r.Network.Required && errors.Is(r.Err, errNotConfigured)
+}
+
+// failed reports whether the network failed, as opposed to being resolved or left out for lack of endpoints.
+func (r resolution) failed() bool {
+	return r.Err != nil && !r.notConfigured()
+}
+
+// provenance returns the audit trail of the block of a successful resolution.
+func (r resolution) provenance() *Provenance {
+	toolVersion, commit := buildVersion()
+
+	provenance := Provenance{
+		Target:      r.Target,
+		Difference:  r.BlockTimestamp - r.Target,
+		DurationMS:  r.Duration.Milliseconds(),
+		ToolVersion: toolVersion,
+		Commit:      commit,
+	}
+
+	for _, health := range r.Endpoints {
+		if health.Successes > 0 {
+			provenance.Endpoints = append(provenance.Endpoints, health.URL)
+		}
+	}
+
+	return &provenance
+}
+
+// resolveOptions controls how resolveAll resolves networks.
+type resolveOptions struct {
+	// Concurrency is the number of networks resolved in parallel.
+	Concurrency int
+	// Timeout is the maximum time spent on a single network.
+	Timeout time.Duration
+	// Cache holds the block timestamps looked up before asking the chains, unless it is nil.
+	Cache *blockcache.Cache
+	// SharedCache, if set, holds block timestamps and resolved blocks shared by every replica of the service,
+	// used instead of Cache.
+	SharedCache *rediscache.Cache
+	// Direction selects the block picked around the target.
+	Direction blockfinder.Direction
+	// Tolerance is the maximum distance between the picked block and the target, or 0 for no limit.
+	Tolerance time.Duration
+	// AlignEpochs moves the start blocks of networks organized in epochs, like VSL, back to the start of their epoch.
+	AlignEpochs bool
+	// CheckArchive checks that the endpoints of a network serve its old blocks before searching them.
+	CheckArchive bool
+	// AnchorContracts raises the start blocks of networks with an anchor contract to the block it was deployed in.
+	AnchorContracts bool
+	// FromGenesis starts every network at the first block of its chain instead of searching for the target.
+	FromGenesis bool
+	// Guard, if set, refuses start blocks that moved too far from their previous value.
+	Guard *changeGuard
+	// Progress, if set, shows the status of every network as it is resolved.
+	Progress *progressBoard
+	// Finality selects what happens to start blocks that aren't final yet.
+	Finality finalityMode
+	// BlockTimeSamples is the number of blocks before the resolved block its average block time is measured over,
+	// or 0 not to measure it.
+	BlockTimeSamples int64
+	// Speculative fetches the blocks of the next two levels of every bisection of a search at once.
+	Speculative bool
+	// Lookups are the block lookup backends, like Etherscan, tried in order before searching the endpoints.
+	Lookups []string
+	// CrossCheck checks resolved start blocks against the cross-check sources of their networks.
+	CrossCheck bool
+	// L1Anchored resolves rollups with an L1 anchor through the block of their L1 at the target.
+	L1Anchored bool
+	// Checkpoint, if set, saves the outcome of every network as it is resolved.
+	Checkpoint *checkpoint
+}
+
+// addResolveFlags adds the flags read by resolveOptionsFromFlags to cmd.
+func addResolveFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("concurrency", 5, "number of networks to resolve in parallel")
+	cmd.Flags().Duration("network-timeout", 5*time.Minute, "maximum time to spend resolving a single network")
+	cmd.Flags().String("cache", defaultCachePath(), "path to the cache of block timestamps kept between runs")
+	cmd.Flags().Bool("no-cache", false, "fetch every block timestamp from the chains instead of the cache")
+	cmd.Flags().String("direction", string(blockfinder.After), "block to pick around the target: after (first block at or after it), before (last block at or before it) or closest")
+	cmd.Flags().Bool("align-epochs", true, "move the start blocks of networks organized in epochs, like VSL, back to the first block of their epoch")
+	cmd.Flags().Bool("anchor-contracts", true, "raise the start blocks of networks with an anchor_contract, like lens, to the block the contract was deployed in")
+	cmd.Flags().Bool("check-archive", true, "check that the endpoints serve old blocks before searching, failing networks whose endpoints are all pruned")
+	cmd.Flags().Bool("from-genesis", false, "start every network at the first block of its chain instead of the target, writing its timestamp to network_genesis_timestamp")
+	cmd.Flags().String("finality", string(finalityRequire), "what to do with start blocks that aren't final yet: require (fail the network), warn or off")
+	cmd.Flags().Int64("block-time-samples", 10, "number of blocks before each start block to measure the average block time over, written to network_block_time (0 to skip)")
+	cmd.Flags().Duration("tolerance", 0, "maximum distance between the picked block and the target, beyond which the network fails (0 for no limit)")
+	cmd.Flags().StringSlice("lookups", defaultLookups, "indexer services to look blocks up with before searching the endpoints, in order, confirming their blocks on the endpoints: etherscan, moralis and covalent for EVM networks with a chain_id once ETHERSCAN_API_KEY, MORALIS_API_KEY or COVALENT_API_KEY is set, and subgraph for networks with a block_subgraph (none to always search)")
+	cmd.Flags().Bool("cross-check", false, "check the timestamp of every start block against the cross_check sources of its network, like Etherscan or Blockscout, failing networks whose sources disagree")
+	cmd.Flags().Bool("l1-anchored", false, "resolve rollups with an l1_anchor, like optimism, base and arbitrum, to the first block derived from the block of their L1 at the target, for boundaries consistent with the L1")
+	cmd.Flags().Bool("speculative", false, "fetch the blocks of the next two levels of every bisection of a search in parallel, saving round trips to slow endpoints for a few more calls")
+}
+
+// resolveOptionsFromFlags returns the resolve options set by the flags added by addResolveFlags,
+// opening the block cache unless disabled. The options must be closed once done.
+func resolveOptionsFromFlags(cmd *cobra.Command) (resolveOptions, error) {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	networkTimeout, _ := cmd.Flags().GetDuration("network-timeout")
+	cachePath, _ := cmd.Flags().GetString("cache")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	directionFlag, _ := cmd.Flags().GetString("direction")
+	tolerance, _ := cmd.Flags().GetDuration("tolerance")
+	alignEpochs, _ := cmd.Flags().GetBool("align-epochs")
+	checkArchive, _ := cmd.Flags().GetBool("check-archive")
+	anchorContracts, _ := cmd.Flags().GetBool("anchor-contracts")
+	fromGenesis, _ := cmd.Flags().GetBool("from-genesis")
+	finalityFlag, _ := cmd.Flags().GetString("finality")
+	blockTimeSamples, _ := cmd.Flags().GetInt64("block-time-samples")
+	speculative, _ := cmd.Flags().GetBool("speculative")
+	crossCheck, _ := cmd.Flags().GetBool("cross-check")
+	l1Anchored, _ := cmd.Flags().GetBool("l1-anchored")
+	lookupsFlag, _ := cmd.Flags().GetStringSlice("lookups")
+
+	if concurrency < 1 {
+		return resolveOptions{}, fmt.Errorf("invalid concurrency %d: must be at least 1", concurrency)
+	}
+
+	if blockTimeSamples < 0 {
+		return resolveOptions{}, fmt.Errorf("invalid number of block time samples %d: must not be negative", blockTimeSamples)
+	}
+
+	direction, err := blockfinder.ParseDirection(directionFlag)
+	if err != nil {
+		return resolveOptions{}, err
+	}
+
+	finality, err := parseFinalityMode(finalityFlag)
+	if err != nil {
+		return resolveOptions{}, err
+	}
+
+	lookups, err := parseLookups(lookupsFlag)
+	if err != nil {
+		return resolveOptions{}, err
+	}
+
+	options := resolveOptions{
+		Concurrency:  concurrency,
+		Timeout:      networkTimeout,
+		Direction:    direction,
+		Tolerance:    tolerance,
+		AlignEpochs:  alignEpochs,
+		CheckArchive: checkArchive,
+		FromGenesis:  fromGenesis,
+		Finality:     finality,
+
+		AnchorContracts:  anchorContracts,
+		BlockTimeSamples: blockTimeSamples,
+		Speculative:      speculative,
+		CrossCheck:       crossCheck,
+		L1Anchored:       l1Anchored,
+		Lookups:          lookups,
+	}
+
+	if !noCache && cachePath != "" {
+		// The cache only saves requests, so resolving goes on without it
+		if options.Cache, err = blockcache.Open(cachePath); err != nil {
+			slog.Warn("Error opening block cache, resolving without it", "path", cachePath, "error", err)
+		}
+	}
+
+	return options, nil
+}
+
+// Close releases the block cache of the options, if any.
+func (o resolveOptions) Close() {
+	if o.Cache != nil {
+		o.Cache.Close()
+	}
+}
+
+// resolveAll resolves every network with a pool of workers. The results are returned in the same order as networks.
+func resolveAll(ctx context.Context, networks []Network, targetTimestamp int64, options resolveOptions) []resolution {
+	results := make([]resolution, len(networks))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				// Once interrupted, the remaining networks are skipped rather than dialed
+				if err := ctx.Err(); err != nil {
+					results[index] = resolution{Network: networks[index], Err: fmt.Errorf("not resolved: %w", err)}
+					continue
+				}
+
+				networkCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+				results[index] = resolveNetwork(networkCtx, networks[index], targetTimestamp, options)
+				cancel()
+
+				options.Checkpoint.record(results[index])
+			}
+		}()
+	}
+
+	for index := range networks {
+		indexes <- index
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	return results
+}
+
+// resolveNetwork finds the block of network in the given direction from targetTimestamp, or from the target of
+// the network if it overrides it.
+func resolveNetwork(ctx context.Context, network Network, targetTimestamp int64, options resolveOptions) (result resolution) {
+	progress := options.Progress.track(network.Name)
+	defer func() {
+		progress.finished(result)
+	}()
+
+	ctx, span := tracer.Start(ctx, "resolve "+network.Name,
+		trace.WithAttributes(attribute.String("network", network.Name), attribute.String("network.type", network.Type)))
+	defer func() {
+		span.SetAttributes(attribute.Int64("target", result.Target), attribute.Int("rpc_calls", result.RPCCalls))
+		if result.Err == nil {
+			span.SetAttributes(attribute.Int64("block", result.Block), attribute.Int64("difference", result.BlockTimestamp-result.Target))
+		}
+
+		endSpan(span, result.Err)
+	}()
+
+	result.Network, result.Target = network, targetTimestamp
+	result.Genesis = options.FromGenesis || network.Target == genesisTarget
+	result.Deployment = !result.Genesis && network.Target == deploymentTarget
+
+	if network.Target != "" && !result.Genesis && !result.Deployment {
+		target, err := parseTimestamp(network.Target)
+		if err != nil {
+			result.Err = fmt.Errorf("error parsing target of network: %w", err)
+			return result
+		}
+
+		result.Target = target
+	}
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	if len(network.URLs) == 0 {
+		result.Err = notConfiguredError(network)
+		return result
+	}
+
+	progress.dialing()
+
+	finder, conn, err := dialNetwork(ctx, network)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer func() {
+		result.RPCCalls = conn.Calls()
+		if reporter, ok := conn.(interface{ Health() []endpoint.Health }); ok {
+			result.Endpoints = reporter.Health()
+		}
+
+		conn.Close()
+	}()
+
+	if err := verifyChainID(ctx, network, finder); err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Pruned nodes fail or answer garbage deep into a search, so they are caught up front
+	if checker, ok := finder.(blockfinder.ArchiveChecker); ok && options.CheckArchive {
+		if err := checker.CheckArchive(ctx); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	if cacheable, ok := finder.(blockfinder.Cacheable); ok {
+		switch {
+		case options.SharedCache != nil:
+			cacheable.SetCache(options.SharedCache.Network(network.Name))
+		case options.Cache != nil:
+			cacheable.SetCache(options.Cache.Network(network.Name))
+		}
+	}
+
+	if progress != nil {
+		progress.searching(conn.Calls)
+		ctx = blockfinder.WithProgress(ctx, progress.narrowed)
+	}
+
+	if options.Speculative {
+		ctx = blockfinder.WithSpeculation(ctx)
+	}
+
+	var block blockfinder.BlockRef
+	if result.Deployment {
+		block, err = deploymentBlock(ctx, network, finder)
+	} else {
+		block, err = findBlock(ctx, network, finder, result.Target, result.Genesis, options)
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	switch {
+	case result.Genesis:
+		slog.Debug("Starting at the genesis of the network", "network", network.Name, "block", block.Number)
+
+		result.Target = block.Timestamp
+	case result.Deployment:
+		slog.Debug("Starting at the deployment of the contracts of the network", "network", network.Name, "block", block.Number)
+
+		result.Target = block.Timestamp
+	}
+
+	// Picking the block before the target may step below the minimum block, as may finders ignoring it
+	if block.Number < network.MinBlock {
+		slog.Debug("Raising block to the minimum block", "network", network.Name, "block", block.Number, "min_block", network.MinBlock)
+
+		block.Number = network.MinBlock
+		if block.Timestamp, err = finder.BlockTimestamp(ctx, block.Number); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	// Blocks started at the deployment of the anchor contract are already anchored
+	if network.AnchorContract != "" && options.AnchorContracts && !result.Deployment {
+		if block, err = anchorBlock(ctx, network, finder, block); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	if aligner, ok := finder.(blockfinder.EpochAligner); ok && options.AlignEpochs {
+		aligned, err := aligner.AlignToEpoch(ctx, block)
+		if err != nil {
+			result.Err = fmt.Errorf("error aligning block %d to its epoch: %v", block.Number, err)
+			return result
+		}
+
+		slog.Debug("Aligned block to the start of its epoch", "network", network.Name, "block", block.Number, "epoch_start", aligned.Number)
+
+		block = aligned
+	}
+
+	if distance := time.Duration(abs(block.Timestamp-result.Target)) * time.Second; options.Tolerance > 0 && distance > options.Tolerance {
+		result.Err = fmt.Errorf("block %d is %s away from the target, beyond the tolerance of %s", block.Number, distance, options.Tolerance)
+		return result
+	}
+
+	if err := options.Guard.check(ctx, network, finder, block); err != nil {
+		result.Err = err
+		return result
+	}
+
+	// A recent target may land on blocks that are later reorganized away
+	if err := checkFinality(ctx, network, finder, block, options.Finality); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if options.CrossCheck {
+		if err := crossCheck(ctx, network, block); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	result.Block = block.Number
+	result.BlockTimestamp = block.Timestamp
+
+	// Farcaster start points are timestamps, which have no block time
+	if options.BlockTimeSamples > 0 && network.Type != "farcaster" {
+		// The block time is a hint, so failing to measure it doesn't fail the network
+		if result.BlockTime, err = averageBlockTime(ctx, finder, block, options.BlockTimeSamples); err != nil {
+			slog.Warn("Error measuring average block time", "network", network.Name, "block", block.Number, "error", err)
+		}
+	}
+
+	return result
+}
+
+// findBlock finds the block of network in the direction of options from timestamp, or its first block if genesis
+// is set. The block lookup backends of options are tried before searching finder.
+func findBlock(ctx context.Context, network Network, finder provider.Source, timestamp int64, genesis bool, options resolveOptions) (blockfinder.BlockRef, error) {
+	// Every block is at or after the Unix epoch, so the first block after it is the first one available
+	if genesis {
+		block, err := finder.FindBlockByTimestamp(ctx, 0)
+		if err != nil {
+			return blockfinder.BlockRef{}, fmt.Errorf("error finding genesis block: %v", err)
+		}
+
+		return block, nil
+	}
+
+	if options.L1Anchored && network.L1Anchor != nil {
+		return l1AnchoredBlock(ctx, network, finder, timestamp, options)
+	}
+
+	block, found := lookupBlock(ctx, network, finder, timestamp, options.Lookups)
+	if !found {
+		var err error
+		if block, err = finder.FindBlockByTimestamp(ctx, timestamp); err != nil {
+			return blockfinder.BlockRef{}, fmt.Errorf("error finding closest block: %v", err)
+		}
+	}
+
+	return blockfinder.Pick(ctx, block, timestamp, options.Direction, finder.BlockTimestamp)
+}
+
+// anchorBlock returns block, or the block the anchor contract of network was deployed in if block is before it.
+// Networks with an anchor event are anchored to the first log of the contract with that topic instead.
+func anchorBlock(ctx context.Context, network Network, finder provider.Source, block blockfinder.BlockRef) (blockfinder.BlockRef, error) {
+	var anchor blockfinder.BlockRef
+
+	if network.AnchorEvent != "" {
+		logFinder, ok := finder.(blockfinder.LogFinder)
+		if !ok {
+			return blockfinder.BlockRef{}, fmt.Errorf("network type %q has no event logs to anchor to", network.Type)
+		}
+
+		var err error
+		if anchor, err = logFinder.FirstLogBlock(ctx, network.AnchorContract, []string{network.AnchorEvent}); err != nil {
+			return blockfinder.BlockRef{}, fmt.Errorf("error finding the first %s log of anchor contract %s: %v", network.AnchorEvent, network.AnchorContract, err)
+		}
+	} else {
+		deployer, ok := finder.(blockfinder.DeploymentFinder)
+		if !ok {
+			return blockfinder.BlockRef{}, fmt.Errorf("network type %q has no contracts to anchor to", network.Type)
+		}
+
+		var err error
+		if anchor, err = deployer.DeploymentBlock(ctx, network.AnchorContract); err != nil {
+			return blockfinder.BlockRef{}, fmt.Errorf("error finding the deployment of anchor contract %s: %v", network.AnchorContract, err)
+		}
+	}
+
+	if block.Number >= anchor.Number {
+		return block, nil
+	}
+
+	slog.Debug("Raising block to the anchor of the network", "network", network.Name, "block", block.Number, "anchor_block", anchor.Number)
+
+	return anchor, nil
+}
+
+// deploymentBlock returns the block the first of the deployment contracts of network was deployed in.
+func deploymentBlock(ctx context.Context, network Network, finder provider.Source) (blockfinder.BlockRef, error) {
+	contracts := network.deploymentContracts()
+	if len(contracts) == 0 {
+		return blockfinder.BlockRef{}, fmt.Errorf("network has no contracts to start at the deployment of, set its anchor_contract")
+	}
+
+	deployer, ok := finder.(blockfinder.DeploymentFinder)
+	if !ok {
+		return blockfinder.BlockRef{}, fmt.Errorf("network type %q has no contracts to start at", network.Type)
+	}
+
+	var first blockfinder.BlockRef
+
+	for index, address := range contracts {
+		deployed, err := deployer.DeploymentBlock(ctx, address)
+		if err != nil {
+			return blockfinder.BlockRef{}, fmt.Errorf("error finding the deployment of contract %s: %v", address, err)
+		}
+
+		slog.Debug("Found deployment of contract", "network", network.Name, "contract", address, "block", deployed.Number)
+
+		if index == 0 || deployed.Number < first.Number {
+			first = deployed
+		}
+	}
+
+	return first, nil
+}
+
+// averageBlockTime measures the average time between the blocks of finder over the samples blocks up to block,
+// or the ones following it if there aren't enough blocks before it.
+func averageBlockTime(ctx context.Context, finder provider.Source, block blockfinder.BlockRef, samples int64) (float64, error) {
+	first, last := block, blockfinder.BlockRef{Number: block.Number - samples}
+	if last.Number < 1 {
+		last.Number = block.Number + samples
+	}
+
+	var err error
+	if last.Timestamp, err = finder.BlockTimestamp(ctx, last.Number); err != nil {
+		return 0, err
+	}
+
+	if last.Number < first.Number {
+		first, last = last, first
+	}
+
+	return float64(last.Timestamp-first.Timestamp) / float64(last.Number-first.Number), nil
+}
+
+// defaultCachePath returns the path of the block timestamp cache in the user cache directory,
+// or an empty path, disabling the cache, if there is no such directory.
+func defaultCachePath() string {
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(directory, "get-node-start-block", "timestamps.db")
+}
+
+// abs returns the absolute value of x.
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
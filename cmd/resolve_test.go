@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"get-node-start-block/pkg/mockchain"
+)
+
+func TestResolveFormats(t *testing.T) {
+	// The target is 1200 seconds after genesis, so block 100 of mainnet and block 200 of base-test
+	mainnet := mockchain.Chain{ChainID: 1, GenesisTime: 1_600_000_000, BlockTime: 12, Height: 10_000}
+	fast := mockchain.Chain{ChainID: 2, GenesisTime: 1_600_000_000, BlockTime: 6, Height: 10_000}
+	target := mainnet.Timestamp(100)
+
+	fixturesPath := writeFixtures(t, map[string]mockchain.Chain{"mainnet": mainnet, "base-test": fast})
+
+	tests := []struct {
+		format string
+		// want are the lines the written file must hold, in order
+		want []string
+	}{
+		{format: "yaml", want: []string{"network_start_block:", "  base-test: 200", "  mainnet: 100"}},
+		{format: "toml", want: []string{"[network_start_block]", "base-test = 200", "mainnet = 100"}},
+		{format: "env", want: []string{"NODE_START_BLOCK_BASE_TEST=200", "NODE_START_BLOCK_MAINNET=100"}},
+		{format: "tfvars", want: []string{"network_start_block = {", "  base-test = 200", "  mainnet   = 100", "}"}},
+		{format: "k8s-configmap", want: []string{"kind: ConfigMap", `  NODE_START_BLOCK_BASE_TEST: "200"`, `  NODE_START_BLOCK_MAINNET: "100"`}},
+		{format: "helm-values", want: []string{"config:", "  network_start_block:", "    base-test: 200", "    mainnet: 100"}},
+		{format: "csv", want: []string{"network,block,block_time,difference,error", "mainnet,100,2020-09-13T12:46:40Z,0,", "base-test,200,2020-09-13T12:46:40Z,0,"}},
+		{format: "md", want: []string{"| mainnet | 100 | 2020-09-13T12:46:40Z | 0s |", "| base-test | 200 | 2020-09-13T12:46:40Z | 0s |"}},
+	}
+
+	newConfig := func(t *testing.T) string {
+		return writeJSON(t, "config.json", map[string]interface{}{
+			"networks": []map[string]interface{}{{"name": "mainnet", "type": "ethereum"}, {"name": "base-test", "type": "ethereum"}},
+		})
+	}
+
+	resolve := func(t *testing.T, args ...string) {
+		t.Helper()
+
+		args = append([]string{"resolve", "--fixtures=" + fixturesPath, "--no-cache", "--lookups", "none",
+			"--timestamp", strconv.FormatInt(target, 10)}, args...)
+		if err := runCommand(t, args...); err != nil {
+			t.Fatalf("resolve error = %v", err)
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		configPath := newConfig(t)
+		resolve(t, "--config", configPath)
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			t.Fatalf("error loading the written config: %v", err)
+		}
+
+		for network, want := range map[string]int64{"mainnet": 100, "base-test": 200} {
+			if got := config.NetworkStartBlock[network]; got.Block != want || got.Timestamp != target || got.Source != startSourceSearch {
+				t.Errorf("start block of %s = %+v, want block %d at %d from a search", network, got, want, target)
+			}
+		}
+
+		if len(config.Networks) != 2 {
+			t.Errorf("written config has %d networks, want the 2 it was read with", len(config.Networks))
+		}
+	})
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			configPath := newConfig(t)
+			outputPath := filepath.Join(t.TempDir(), "output."+test.format)
+
+			resolve(t, "--config", configPath, "--format", test.format, "--output", outputPath)
+
+			content, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("error reading the written %s: %v", test.format, err)
+			}
+
+			lines := strings.Split(string(content), "\n")
+
+			// Every wanted line is looked for after the previous one
+			next := 0
+			for _, want := range test.want {
+				found := false
+				for ; next < len(lines) && !found; next++ {
+					found = lines[next] == want
+				}
+
+				if !found {
+					t.Fatalf("written %s is missing %q in order:\n%s", test.format, want, content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the config from its most recent backup",
+	Long: `Restore the config from its most recent backup, which resolve takes every time it updates the config.
+The backup is consumed, so running rollback again restores the one before it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		if path == "" {
+			path = configPath
+		}
+
+		paths, err := backups(path)
+		if err != nil {
+			return fmt.Errorf("error listing backups: %w", err)
+		}
+
+		if len(paths) == 0 {
+			return fmt.Errorf("no backups of %s found", path)
+		}
+
+		latest := paths[len(paths)-1]
+
+		content, err := os.ReadFile(latest)
+		if err != nil {
+			return fmt.Errorf("error reading backup: %w", err)
+		}
+
+		if err := writeFile(path, content); err != nil {
+			return err
+		}
+
+		if err := os.Remove(latest); err != nil {
+			return fmt.Errorf("error removing restored backup: %w", err)
+		}
+
+		slog.Info("Config file restored", "path", path, "backup", latest, "backups_left", len(paths)-1)
+
+		return nil
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().String("file", "", "path of the file to restore, e.g. the --output of a resolve run (defaults to --config)")
+
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/provider"
+)
+
+// L1Anchor tells which L1 an optimistic rollup derives its blocks from, for --l1-anchored to resolve the rollup
+// through the L1 block at the target.
+type L1Anchor struct {
+	// Network is the name of the L1 network, among the networks of the config or profile.
+	Network string `json:"network"`
+	// Rollup is the stack of the rollup, op-stack or arbitrum, which tells how its blocks map to L1 blocks.
+	Rollup string `json:"rollup"`
+}
+
+// l1AnchoredBlock returns the first block of the rollup network derived from the block of its L1 in the direction
+// of options from timestamp, so that the start blocks of rollups sharing an L1 cut their history at the same L1 block.
+func l1AnchoredBlock(ctx context.Context, network Network, finder provider.Source, timestamp int64, options resolveOptions) (blockfinder.BlockRef, error) {
+	if network.L1 == nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("L1 network %q of the rollup isn't among the networks", network.L1Anchor.Network)
+	}
+
+	// The rollup was validated when loading the config
+	rollup, _ := blockfinder.ParseRollup(network.L1Anchor.Rollup)
+
+	mapper, ok := finder.(blockfinder.L1Mapper)
+	if !ok {
+		return blockfinder.BlockRef{}, fmt.Errorf("network type %q can't be mapped to an L1", network.Type)
+	}
+
+	l1 := *network.L1
+
+	// A rollup whose L1 has no endpoints is left out like networks without endpoints of their own, unless required
+	if len(l1.URLs) == 0 {
+		return blockfinder.BlockRef{}, fmt.Errorf("L1 %s: %w", l1.Name, notConfiguredError(l1))
+	}
+
+	l1Finder, conn, err := dialNetwork(ctx, l1)
+	if err != nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("error connecting to L1 %s: %w", l1.Name, err)
+	}
+	defer conn.Close()
+
+	if err := verifyChainID(ctx, l1, l1Finder); err != nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("error verifying L1 %s: %w", l1.Name, err)
+	}
+
+	if cacheable, ok := l1Finder.(blockfinder.Cacheable); ok {
+		switch {
+		case options.SharedCache != nil:
+			cacheable.SetCache(options.SharedCache.Network(l1.Name))
+		case options.Cache != nil:
+			cacheable.SetCache(options.Cache.Network(l1.Name))
+		}
+	}
+
+	l1Block, err := l1Finder.FindBlockByTimestamp(ctx, timestamp)
+	if err != nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("error finding L1 block on %s: %v", l1.Name, err)
+	}
+
+	if l1Block, err = blockfinder.Pick(ctx, l1Block, timestamp, options.Direction, l1Finder.BlockTimestamp); err != nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("error finding L1 block on %s: %v", l1.Name, err)
+	}
+
+	block, err := mapper.L2BlockForL1(ctx, rollup, l1Block.Number)
+	if err != nil {
+		return blockfinder.BlockRef{}, fmt.Errorf("error mapping L1 block %d of %s: %v", l1Block.Number, l1.Name, err)
+	}
+
+	slog.Debug("Mapped L1 block to the network", "network", network.Name, "l1", l1.Name, "l1_block", l1Block.Number, "block", block.Number)
+
+	return block, nil
+}
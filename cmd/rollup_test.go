@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/mockchain"
+)
+
+func TestL1AnchoredNotConfigured(t *testing.T) {
+	chain := mockchain.Chain{ChainID: 8453, GenesisTime: 1_600_000_000, BlockTime: 2, Height: 10_000}
+
+	server := mockchain.NewServer(chain)
+	defer server.Close()
+
+	activeFixtures = nil
+
+	config, err := loadConfig(writeJSON(t, "config.json", map[string]interface{}{
+		"networks": []map[string]interface{}{
+			{"name": "ethereum", "type": "ethereum", "env": "ROLLUP_TEST_UNSET_RPC_URL"},
+			{
+				"name": "base", "type": "ethereum", "urls": []string{server.URL()},
+				"l1_anchor": map[string]string{"network": "ethereum", "rollup": "op-stack"},
+			},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	options := resolveOptions{Concurrency: 1, Timeout: time.Minute, Direction: blockfinder.After, Finality: finalityOff, L1Anchored: true}
+
+	for _, required := range []bool{false, true} {
+		networkList, err := selectNetworks(networks(config), []string{"ethereum"}, nil)
+		if err != nil {
+			t.Fatalf("selectNetworks() error = %v", err)
+		}
+
+		networkList[0].Required = required
+
+		result := resolveAll(context.Background(), networkList, chain.Timestamp(100), options)[0]
+		if result.Network.Name != "base" {
+			t.Fatalf("resolveAll() resolved %s, want base", result.Network.Name)
+		}
+
+		// The rollup is left out like its L1, which has no endpoints, and fails only if required
+		if result.notConfigured() == required || result.failed() != required {
+			t.Errorf("resolution of base required=%t = %v, want not configured=%t", required, result.Err, !required)
+		}
+	}
+}
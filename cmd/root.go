@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"get-node-start-block/pkg/retry"
+)
+
+// defaultTimestamp is the target time used when --timestamp is not given.
+const defaultTimestamp = "1717200000"
+
+var (
+	configPath  string
+	profileName string
+	// activeProfile is the profile selected by --profile.
+	activeProfile profile
+	logLevel      string
+	logFormat     string
+	retryPolicy   = retry.DefaultPolicy
+	callTimeout   time.Duration
+	racing        int
+	fixturesPath  string
+)
+
+var rootCmd = &cobra.Command{
+	Use:          "get-node-start-block",
+	Short:        "Resolve RSS3 Node network start blocks for a target timestamp",
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Load .env file, whose variables may set flags
+		dotenvErr := loadDotenv()
+
+		if err := applySettings(cmd, nil); err != nil {
+			return err
+		}
+
+		if err := setupLogger(logLevel, logFormat); err != nil {
+			return err
+		}
+
+		if dotenvErr != nil {
+			slog.Debug("Error loading .env file", "error", dotenvErr)
+			// Continue execution even if .env file is not found
+		}
+
+		var err error
+		if activeProfile, err = lookupProfile(profileName); err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("config") {
+			configPath = activeProfile.ConfigPath
+		}
+
+		fileSettings, err := readFileSettings(configPath)
+		if err != nil {
+			return err
+		}
+
+		if err := applySettings(cmd, fileSettings); err != nil {
+			return err
+		}
+
+		// The config file may have changed how to log
+		if err := setupLogger(logLevel, logFormat); err != nil {
+			return err
+		}
+
+		if err := setupTracing(cmd.Context()); err != nil {
+			return err
+		}
+
+		if fixturesPath != "" {
+			if activeFixtures, err = loadFixtures(fixturesPath); err != nil {
+				return err
+			}
+
+			slog.Warn("Serving every network from a mock chain instead of its endpoints", "fixtures", fixturesPath)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", profiles[defaultProfile].ConfigPath, "path to the config file (defaults to the config file of the profile), whose settings section sets flags left off the command line and out of the environment")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", defaultProfile, "set of networks to resolve when the config file lists none: mainnet or testnet")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "minimum level of logged messages: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "format of logged messages: text or json")
+	rootCmd.PersistentFlags().IntVar(&retryPolicy.Attempts, "max-attempts", retryPolicy.Attempts, "maximum number of attempts of every RPC call, across all endpoints of a network")
+	rootCmd.PersistentFlags().DurationVar(&callTimeout, "call-timeout", 30*time.Second, "maximum time a single RPC call to an endpoint may take before failing over (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&racing, "race", 1, "number of endpoints of a network to send every RPC call to at once, taking the first answer and logging endpoints that disagree with it")
+	rootCmd.PersistentFlags().StringVar(&fixturesPath, "fixtures", "", "serve every network from a local mock EVM chain, as described by the fixtures file given as --fixtures=<file>, or the default chain if given without one")
+	rootCmd.PersistentFlags().Lookup("fixtures").NoOptDefVal = defaultFixtures
+	rootCmd.PersistentFlags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP endpoint to export traces of every run, network and RPC call to, like http://collector:4318 (tracing is off unless set here or in OTEL_EXPORTER_OTLP_ENDPOINT)")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.BaseDelay, "retry-delay", retryPolicy.BaseDelay, "delay before the first retry of a failed RPC call, doubled for every further retry")
+}
+
+// Execute runs the root command and exits with a non-zero code on failure: exitFatal, or the code of an exitError.
+//
+// The command's context is canceled on the first SIGINT or SIGTERM, letting it wind down and keep what it
+// resolved so far. A second signal terminates the process straight away.
+func Execute() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+
+	// Spans are exported in batches, so the last ones are still to be sent
+	stopTracing()
+
+	if err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
+
+		os.Exit(exitFatal)
+	}
+}
+
+// setupLogger makes slog, and the log package along with it, write messages of at least level to stderr in format.
+func setupLogger(level, format string) error {
+	var minimumLevel slog.Level
+	if err := minimumLevel.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+
+	options := slog.HandlerOptions{Level: minimumLevel}
+
+	switch format {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(logOutput{}, &options)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(logOutput{}, &options)))
+	default:
+		return fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+
+	return nil
+}
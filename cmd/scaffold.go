@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Generate the endpoints and worker sections of an RSS3 Node config, with the start block of every network",
+	Long: `Generate the endpoints and worker sections of an RSS3 Node config for every network, resolving their
+start blocks for the target timestamp. Endpoint URLs are left as placeholders, as they usually carry API keys.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timestampFlag, _ := cmd.Flags().GetString("timestamp")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		targetTimestamp, err := parseTimestamp(timestampFlag)
+		if err != nil {
+			return fmt.Errorf("error parsing target timestamp: %w", err)
+		}
+		slog.Info("Resolving start blocks", "target", targetTimestamp, "target_time", time.Unix(targetTimestamp, 0).UTC().Format(time.RFC3339))
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		options, err := resolveOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer options.Close()
+
+		results := resolveAll(cmd.Context(), networks(config), targetTimestamp, options)
+
+		var failed int
+		for _, result := range results {
+			if result.Err != nil {
+				slog.Error("Error resolving network, leaving its worker without a start", "network", result.Network.Name, "error", result.Err)
+				failed++
+			}
+		}
+
+		content, err := marshalNodeConfig(newNodeConfig(results))
+		if err != nil {
+			return err
+		}
+
+		if outputPath == "" {
+			os.Stdout.Write(content)
+		} else {
+			if err := writeFile(outputPath, content); err != nil {
+				return err
+			}
+
+			slog.Info("Node config written", "path", outputPath)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to resolve %d networks", failed)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	scaffoldCmd.Flags().String("timestamp", defaultTimestamp, "target time as Unix seconds, RFC3339 date (e.g. 2024-06-01T00:00:00Z) or time before now (e.g. now-30d)")
+	scaffoldCmd.Flags().String("output", "", "path to write the node config to (defaults to stdout)")
+	addResolveFlags(scaffoldCmd)
+
+	rootCmd.AddCommand(scaffoldCmd)
+}
+
+// nodeConfig holds the sections of an RSS3 Node config describing its workers.
+type nodeConfig struct {
+	Endpoints map[string]nodeEndpoint `yaml:"endpoints"`
+	Component nodeComponent           `yaml:"component"`
+}
+
+type nodeEndpoint struct {
+	URL string `yaml:"url"`
+}
+
+type nodeComponent struct {
+	Decentralized []nodeWorker `yaml:"decentralized"`
+}
+
+// nodeWorker is a worker of an RSS3 Node, indexing a network from an endpoint.
+type nodeWorker struct {
+	ID       string `yaml:"id"`
+	Network  string `yaml:"network"`
+	Endpoint string `yaml:"endpoint"`
+	Worker   string `yaml:"worker"`
+	// Parameters holds the start of the worker, under the key the worker of the network reads it from.
+	Parameters map[string]int64 `yaml:"parameters,omitempty"`
+}
+
+// newNodeConfig returns a node config with an endpoint and a worker for every resolved network.
+// Workers of networks that failed to resolve have no start.
+func newNodeConfig(results []resolution) *nodeConfig {
+	config := nodeConfig{Endpoints: make(map[string]nodeEndpoint, len(results))}
+
+	for _, result := range results {
+		network := result.Network
+
+		config.Endpoints[network.Name] = nodeEndpoint{URL: endpointPlaceholder(network)}
+
+		worker := nodeWorker{
+			ID:       network.Name + "-" + network.Worker,
+			Network:  network.Name,
+			Endpoint: network.Name,
+			Worker:   network.Worker,
+		}
+
+		if result.Err == nil {
+			worker.Parameters = map[string]int64{startParameter(network.Type): result.Block}
+		}
+
+		config.Component.Decentralized = append(config.Component.Decentralized, worker)
+	}
+
+	return &config
+}
+
+// marshalNodeConfig encodes config as YAML, indented like the RSS3 Node example config.
+func marshalNodeConfig(config *nodeConfig) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(config); err != nil {
+		return nil, fmt.Errorf("error marshaling node config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error marshaling node config: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// startParameter returns the worker parameter holding the start of networks of networkType.
+func startParameter(networkType string) string {
+	// Farcaster has no blocks, so its workers start from a timestamp
+	if networkType == "farcaster" {
+		return "timestamp_start"
+	}
+
+	return "block_start"
+}
+
+// endpointPlaceholder returns the placeholder for the endpoint URL of network in a scaffolded config.
+func endpointPlaceholder(network Network) string {
+	if network.Env != "" {
+		return "${" + network.Env + "}"
+	}
+
+	return "${" + strings.ToUpper(strings.ReplaceAll(network.Name, "-", "_")) + "_URL}"
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/rediscache"
+	"get-node-start-block/pkg/startblockpb"
+)
+
+const (
+	// lockPollInterval is the time between two checks for the block of a network being resolved by another replica.
+	lockPollInterval = 250 * time.Millisecond
+	// defaultLockTTL bounds the time a replica holds the lock of resolving a block without a --network-timeout.
+	defaultLockTTL = 5 * time.Minute
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the resolution of blocks by timestamp over gRPC",
+	Long: `Serve the StartBlockService of proto/startblock/v1/startblock.proto over gRPC, so that the RSS3 Node can resolve
+blocks as an internal service. The networks are read from the config file for every call, and resolved with the
+same options as resolve. With --redis-url, block timestamps and resolved blocks are cached in Redis, shared by
+every replica of the service, and a block asked for by many callers at once is only resolved by one of them.
+Networks without endpoints are left out of ResolveAll unless listed with --require, and ResolveBlock answers
+FAILED_PRECONDITION for them, telling a missing config apart from an unavailable endpoint.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		address, _ := cmd.Flags().GetString("grpc-addr")
+		redisURL, _ := cmd.Flags().GetString("redis-url")
+		redisTTL, _ := cmd.Flags().GetDuration("redis-ttl")
+		if redisURL == "" {
+			redisURL = os.Getenv("REDIS_URL")
+		}
+
+		skip, require, err := selectFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		options, err := resolveOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer options.Close()
+
+		if redisURL != "" {
+			if options.SharedCache, err = rediscache.Open(cmd.Context(), redisURL, redisTTL); err != nil {
+				return err
+			}
+			defer options.SharedCache.Close()
+		}
+
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+
+		server := grpc.NewServer()
+		startblockpb.RegisterStartBlockServiceServer(server, &startBlockServer{options: options, skip: skip, require: require})
+
+		go func() {
+			<-cmd.Context().Done()
+
+			slog.Info("Stopping gRPC server")
+			server.GracefulStop()
+		}()
+
+		slog.Info("Serving gRPC", "address", listener.Addr().String())
+
+		return server.Serve(listener)
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("grpc-addr", ":50051", "address to serve gRPC on")
+	serveCmd.Flags().String("redis-url", "", "Redis server to cache block timestamps and resolved blocks in, shared by every replica, like redis://:password@redis:6379/0 (defaults to REDIS_URL)")
+	serveCmd.Flags().Duration("redis-ttl", 24*time.Hour, "time block timestamps and resolved blocks are kept in Redis for")
+	addResolveFlags(serveCmd)
+	addSelectFlags(serveCmd)
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+// startBlockServer implements the StartBlockService by resolving the networks of the config file.
+type startBlockServer struct {
+	startblockpb.UnimplementedStartBlockServiceServer
+
+	options resolveOptions
+	// skip and require select the networks of the config file, as --skip and --require do for resolve.
+	skip, require []string
+	// resolving deduplicates the concurrent calls for the same block.
+	resolving singleflight.Group
+}
+
+// ResolveBlock implements startblockpb.StartBlockServiceServer.
+func (s *startBlockServer) ResolveBlock(ctx context.Context, request *startblockpb.ResolveBlockRequest) (*startblockpb.BlockRef, error) {
+	if err := validTimestamp(request.Timestamp); err != nil {
+		return nil, err
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	network, err := lookupNetwork(config, request.Network)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if slices.Contains(s.skip, network.Name) {
+		return nil, status.Errorf(codes.FailedPrecondition, "network %q is skipped", network.Name)
+	}
+
+	if len(network.URLs) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, notConfiguredError(network).Error())
+	}
+
+	// Networks are resolved for the timestamp asked for, not for a target of their own
+	network.Target = ""
+
+	block, err := s.resolveBlock(ctx, network, request.Timestamp)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &startblockpb.BlockRef{Number: block.Number, Timestamp: block.Timestamp}, nil
+}
+
+// resolveBlock resolves the block of network at timestamp once for all the concurrent calls asking for it, from
+// the shared cache if another replica resolved it already.
+func (s *startBlockServer) resolveBlock(ctx context.Context, network Network, timestamp int64) (blockfinder.BlockRef, error) {
+	cache, direction := s.options.SharedCache, string(s.options.Direction)
+
+	if cache != nil {
+		if block, ok := cache.Block(ctx, network.Name, direction, timestamp); ok {
+			return block, nil
+		}
+	}
+
+	// The first caller resolves for all of them, so its going away must not fail the others
+	resolved, err, _ := s.resolving.Do(fmt.Sprintf("%s:%d", network.Name, timestamp), func() (interface{}, error) {
+		return s.resolveLocked(context.WithoutCancel(ctx), network, timestamp)
+	})
+	if err != nil {
+		return blockfinder.BlockRef{}, err
+	}
+
+	return resolved.(blockfinder.BlockRef), nil
+}
+
+// resolveLocked resolves the block of network at timestamp holding its lock in the shared cache, if any, waiting
+// for the block of the replica holding it otherwise.
+func (s *startBlockServer) resolveLocked(ctx context.Context, network Network, timestamp int64) (blockfinder.BlockRef, error) {
+	cache, direction := s.options.SharedCache, string(s.options.Direction)
+
+	if cache != nil {
+		// The lock outlives the search only if the search hangs past its timeout
+		lockTTL := s.options.Timeout
+		if lockTTL <= 0 {
+			lockTTL = defaultLockTTL
+		}
+
+		unlock, locked := cache.Lock(ctx, network.Name, direction, timestamp, lockTTL)
+		for !locked {
+			select {
+			case <-ctx.Done():
+				return blockfinder.BlockRef{}, ctx.Err()
+			case <-time.After(lockPollInterval):
+			}
+
+			if block, ok := cache.Block(ctx, network.Name, direction, timestamp); ok {
+				return block, nil
+			}
+
+			// The lock expires if its holder goes away, which leaves the block to this replica
+			unlock, locked = cache.Lock(ctx, network.Name, direction, timestamp, lockTTL)
+		}
+		defer unlock()
+
+		// The holder of the lock may have cached the block just before it was taken
+		if block, ok := cache.Block(ctx, network.Name, direction, timestamp); ok {
+			return block, nil
+		}
+	}
+
+	result := resolveAll(ctx, []Network{network}, timestamp, s.options)[0]
+	if result.Err != nil {
+		return blockfinder.BlockRef{}, result.Err
+	}
+
+	block := blockfinder.BlockRef{Number: result.Block, Timestamp: result.BlockTimestamp}
+	if cache != nil {
+		cache.SetBlock(ctx, network.Name, direction, timestamp, block)
+	}
+
+	return block, nil
+}
+
+// ResolveAll implements startblockpb.StartBlockServiceServer. Networks that fail are reported in the errors
+// of the params rather than failing the call, and networks without endpoints are left out unless required.
+func (s *startBlockServer) ResolveAll(ctx context.Context, request *startblockpb.ResolveAllRequest) (*startblockpb.Params, error) {
+	if err := validTimestamp(request.Timestamp); err != nil {
+		return nil, err
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	networkList, err := selectNetworks(networks(config), s.skip, s.require)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	params := startblockpb.Params{
+		NetworkStartBlock: make(map[string]int64),
+		Errors:            make(map[string]string),
+	}
+
+	cache, direction := s.options.SharedCache, string(s.options.Direction)
+
+	// Only the networks missing from the shared cache are resolved. Networks with a target of their own are
+	// resolved for it, so they aren't cached as resolved for the timestamp asked for.
+	var uncached []Network
+	for _, network := range networkList {
+		if cache != nil && network.Target == "" {
+			if block, ok := cache.Block(ctx, network.Name, direction, request.Timestamp); ok {
+				params.NetworkStartBlock[network.Name] = block.Number
+				continue
+			}
+		}
+
+		uncached = append(uncached, network)
+	}
+
+	for _, result := range resolveAll(ctx, uncached, request.Timestamp, s.options) {
+		if result.notConfigured() {
+			slog.Debug("Network not configured, leaving it out", "network", result.Network.Name, "reason", result.Err)
+			continue
+		}
+
+		if result.Err != nil {
+			params.Errors[result.Network.Name] = result.Err.Error()
+			continue
+		}
+
+		params.NetworkStartBlock[result.Network.Name] = result.Block
+
+		if cache != nil && result.Network.Target == "" {
+			cache.SetBlock(ctx, result.Network.Name, direction, request.Timestamp, blockfinder.BlockRef{Number: result.Block, Timestamp: result.BlockTimestamp})
+		}
+	}
+
+	return &params, nil
+}
+
+// validTimestamp checks timestamp as --timestamp is checked, returning an InvalidArgument status if it's invalid.
+func validTimestamp(timestamp int64) error {
+	if _, err := parseTimestamp(strconv.FormatInt(timestamp, 10)); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
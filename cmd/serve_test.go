@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/mockchain"
+	"get-node-start-block/pkg/startblockpb"
+)
+
+func TestServeNotConfigured(t *testing.T) {
+	chain := mockchain.Chain{ChainID: 1, GenesisTime: 1_600_000_000, BlockTime: 12, Height: 10_000}
+
+	server := mockchain.NewServer(chain)
+	defer server.Close()
+
+	path := writeJSON(t, "config.json", map[string]interface{}{
+		"networks": []map[string]interface{}{
+			{"name": "configured", "type": "ethereum", "urls": []string{server.URL()}},
+			{"name": "unconfigured", "type": "ethereum", "env": "SERVE_TEST_UNSET_RPC_URL"},
+		},
+	})
+
+	previousConfigPath := configPath
+	configPath = path
+	defer func() { configPath = previousConfigPath }()
+
+	activeFixtures = nil
+
+	service := &startBlockServer{options: resolveOptions{
+		Concurrency: 1,
+		Timeout:     time.Minute,
+		Direction:   blockfinder.After,
+		Finality:    finalityOff,
+	}}
+
+	timestamp := chain.Timestamp(100)
+
+	t.Run("resolve block", func(t *testing.T) {
+		block, err := service.ResolveBlock(context.Background(), &startblockpb.ResolveBlockRequest{Network: "configured", Timestamp: timestamp})
+		if err != nil {
+			t.Fatalf("ResolveBlock() error = %v", err)
+		}
+
+		if block.Number != 100 {
+			t.Errorf("ResolveBlock() = block %d, want 100", block.Number)
+		}
+	})
+
+	t.Run("resolve block without endpoints", func(t *testing.T) {
+		_, err := service.ResolveBlock(context.Background(), &startblockpb.ResolveBlockRequest{Network: "unconfigured", Timestamp: timestamp})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("ResolveBlock() error = %v, want FailedPrecondition", err)
+		}
+	})
+
+	t.Run("resolve all", func(t *testing.T) {
+		params, err := service.ResolveAll(context.Background(), &startblockpb.ResolveAllRequest{Timestamp: timestamp})
+		if err != nil {
+			t.Fatalf("ResolveAll() error = %v", err)
+		}
+
+		if block, ok := params.NetworkStartBlock["configured"]; !ok || block != 100 {
+			t.Errorf("ResolveAll() start block of configured = %d, want 100", block)
+		}
+
+		if _, ok := params.NetworkStartBlock["unconfigured"]; ok {
+			t.Errorf("ResolveAll() resolved unconfigured, want it left out")
+		}
+
+		if message, ok := params.Errors["unconfigured"]; ok {
+			t.Errorf("ResolveAll() failed unconfigured with %q, want it left out", message)
+		}
+	})
+
+	t.Run("resolve all requiring a network without endpoints", func(t *testing.T) {
+		required := &startBlockServer{options: service.options, require: []string{"unconfigured"}}
+
+		params, err := required.ResolveAll(context.Background(), &startblockpb.ResolveAllRequest{Timestamp: timestamp})
+		if err != nil {
+			t.Fatalf("ResolveAll() error = %v", err)
+		}
+
+		if _, ok := params.Errors["unconfigured"]; !ok {
+			t.Errorf("ResolveAll() left out required network unconfigured, want it failed")
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// settingsEnvPrefix starts the names of the environment variables setting flags, followed by the flag name
+// in upper case with dashes as underscores, like NODE_START_BLOCK_CONCURRENCY for --concurrency.
+const settingsEnvPrefix = "NODE_START_BLOCK_"
+
+// Sources of settings, from the highest precedence to the lowest.
+const (
+	settingSourceFlag    = "flag"
+	settingSourceEnv     = "env"
+	settingSourceDotenv  = ".env"
+	settingSourceFile    = "config file"
+	settingSourceDefault = "default"
+)
+
+// fileExcluded are the flags the settings of the config file can't set, as they choose the config file.
+var fileExcluded = map[string]bool{"config": true, "profile": true}
+
+// appliedSettings holds the source of the flags set by applySettings rather than on the command line.
+var appliedSettings = make(map[string]string)
+
+// setting is the effective value of a flag and where it comes from.
+type setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration of the tool",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [command]",
+	Short: "Print the effective settings of a command and where each comes from",
+	Long: `Print the value every flag of a command would have, resolve by default, and where it comes from.
+Flags are set, from the highest precedence to the lowest, on the command line, by environment variables named
+` + settingsEnvPrefix + `<FLAG> (like ` + settingsEnvPrefix + `CONCURRENCY for --concurrency), by the same variables in the
+.env file, by the settings section of the config file, or else take their defaults.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := resolveCmd
+		if len(args) > 0 {
+			found, _, err := rootCmd.Find(args)
+			if err != nil || found == rootCmd {
+				return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+			}
+
+			target = found
+		}
+
+		fileSettings, err := readFileSettings(configPath)
+		if err != nil {
+			return err
+		}
+
+		// The global flags were parsed for this command, the other ones of the target are at their defaults
+		flags := pflag.NewFlagSet(target.Name(), pflag.ContinueOnError)
+		flags.AddFlagSet(cmd.Flags())
+		flags.AddFlagSet(target.NonInheritedFlags())
+
+		settings := effectiveSettings(flags, fileSettings)
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "SETTING\tVALUE\tSOURCE")
+
+		for _, setting := range settings {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Name, setting.Value, setting.Source)
+		}
+
+		return writer.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// settingEnv returns the environment variable setting the flag named name.
+func settingEnv(name string) string {
+	return settingsEnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// readFileSettings reads the settings section of the config file at path, or none if there is no such file.
+func readFileSettings(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var file struct {
+		Settings map[string]interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	settings := make(map[string]string, len(file.Settings))
+	for name, value := range file.Settings {
+		if fileExcluded[name] {
+			return nil, fmt.Errorf("error parsing config file: setting %q can't be set in the config file", name)
+		}
+
+		if settings[name], err = settingValue(value); err != nil {
+			return nil, fmt.Errorf("error parsing config file: setting %q: %w", name, err)
+		}
+	}
+
+	return settings, nil
+}
+
+// settingValue formats a value of the settings section as the argument of its flag. Lists become
+// comma-separated values, as list flags take them.
+func settingValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			formatted, err := settingValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, formatted)
+		}
+
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("must be a string, number, boolean or list of them")
+	}
+}
+
+// effectiveSettings returns the value of every flag of flags and where it comes from, sorted by name:
+// the command line, the environment, the .env file, the settings of the config file or the default.
+func effectiveSettings(flags *pflag.FlagSet, fileSettings map[string]string) []setting {
+	var settings []setting
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		current := setting{Name: flag.Name, Value: flag.DefValue, Source: settingSourceDefault}
+
+		env := settingEnv(flag.Name)
+
+		if flag.Changed {
+			current.Value, current.Source = flag.Value.String(), settingSourceFlag
+			if source, ok := appliedSettings[flag.Name]; ok {
+				current.Source = source
+			}
+		} else if value, ok := os.LookupEnv(env); ok {
+			current.Value, current.Source = value, settingSourceEnv
+			if dotenvKeys[env] {
+				current.Source = settingSourceDotenv
+			}
+		} else if value, ok := fileSettings[flag.Name]; ok {
+			current.Value, current.Source = value, settingSourceFile
+		}
+
+		settings = append(settings, current)
+	})
+
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Name < settings[j].Name
+	})
+
+	return settings
+}
+
+// applySettings sets the flags of cmd left off the command line from the environment, the .env file and the
+// settings of the config file, in that order of precedence. The .env file must be loaded first. It may run again
+// once the config file is known, which leaves the flags it set before as they are.
+func applySettings(cmd *cobra.Command, fileSettings map[string]string) error {
+	for name := range fileSettings {
+		if !isCommandFlag(cmd.Root(), name) {
+			return fmt.Errorf("error parsing config file: unknown setting %q", name)
+		}
+	}
+
+	for _, setting := range effectiveSettings(cmd.Flags(), fileSettings) {
+		if cmd.Flags().Changed(setting.Name) || setting.Source == settingSourceDefault {
+			continue
+		}
+
+		if err := cmd.Flags().Set(setting.Name, setting.Value); err != nil {
+			return fmt.Errorf("invalid %s setting %s=%q: %w", setting.Source, setting.Name, setting.Value, err)
+		}
+
+		appliedSettings[setting.Name] = setting.Source
+	}
+
+	return nil
+}
+
+// isCommandFlag returns whether root or any of its subcommands has a flag named name, so that the settings of
+// the config file can hold the flags of every command.
+func isCommandFlag(root *cobra.Command, name string) bool {
+	found := false
+
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+			found = true
+		}
+
+		for _, child := range cmd.Commands() {
+			visit(child)
+		}
+	}
+	visit(root)
+
+	return found
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+// Algorithms of detached signatures.
+const (
+	signatureEd25519 = "ed25519"
+	// signatureEthereum signs with a secp256k1 key as personal_sign does, so that wallets and contracts can check
+	// the signature too.
+	signatureEthereum = "ethereum"
+)
+
+// signatureSuffix is appended to the path of a config file to name its detached signature.
+const signatureSuffix = ".sig"
+
+// detachedSignature is the signature of the canonical JSON of a config file, written next to it.
+type detachedSignature struct {
+	Algorithm string `json:"algorithm"`
+	// PublicKey is the hex-encoded public key of ed25519 signatures.
+	PublicKey string `json:"public_key,omitempty"`
+	// Address is the address of the key of Ethereum signatures.
+	Address   string `json:"address,omitempty"`
+	Signature string `json:"signature"`
+}
+
+// signingKey is the private key configs are signed with, either an ed25519 or an Ethereum key.
+type signingKey struct {
+	ed25519  ed25519.PrivateKey
+	ethereum *ecdsa.PrivateKey
+}
+
+var verifySignatureCmd = &cobra.Command{
+	Use:   "verify-signature <config>",
+	Short: "Check the detached signature of a config file",
+	Long: `Check that the detached signature of a config file, written by resolve --sign-key, was made by the expected key
+over the current content of the file. The content is compared as canonical JSON, so formatting changes don't matter.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		signaturePath, _ := cmd.Flags().GetString("signature")
+		publicKey, _ := cmd.Flags().GetString("public-key")
+		address, _ := cmd.Flags().GetString("address")
+
+		if (publicKey == "") == (address == "") {
+			return fmt.Errorf("exactly one of --public-key and --address must be given")
+		}
+
+		if address != "" && !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address %q", address)
+		}
+
+		if signaturePath == "" {
+			signaturePath = args[0] + signatureSuffix
+		}
+
+		signature, err := readSignature(signaturePath)
+		if err != nil {
+			return err
+		}
+
+		// The signature is only as trustworthy as the key it names, so that key must be the expected one
+		switch {
+		case publicKey != "" && (signature.Algorithm != signatureEd25519 || !strings.EqualFold(signature.PublicKey, publicKey)):
+			return fmt.Errorf("config is not signed by public key %s", publicKey)
+		case address != "" && (signature.Algorithm != signatureEthereum || !strings.EqualFold(signature.Address, address)):
+			return fmt.Errorf("config is not signed by address %s", address)
+		}
+
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+
+		canonical, err := canonicalJSON(content)
+		if err != nil {
+			return err
+		}
+
+		if err := signature.verify(canonical); err != nil {
+			return err
+		}
+
+		fmt.Println("Signature is valid.")
+
+		return nil
+	},
+}
+
+func init() {
+	verifySignatureCmd.Flags().String("signature", "", "path of the detached signature (defaults to the config path with "+signatureSuffix+" appended)")
+	verifySignatureCmd.Flags().String("public-key", "", "hex-encoded ed25519 public key the config must be signed with")
+	verifySignatureCmd.Flags().String("address", "", "address of the Ethereum key the config must be signed with")
+
+	rootCmd.AddCommand(verifySignatureCmd)
+}
+
+// loadSigningKey reads the private key at path, either an ed25519 key in a PKCS #8 PEM file, as written by
+// "openssl genpkey -algorithm ed25519", or a hex-encoded Ethereum private key.
+func loadSigningKey(path string) (*signingKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing key: %w", err)
+	}
+
+	if block, _ := pem.Decode(content); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key: %w", err)
+		}
+
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("error parsing signing key: %T keys aren't supported, only ed25519 ones", key)
+		}
+
+		return &signingKey{ed25519: privateKey}, nil
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signing key: not an ed25519 PEM file nor an Ethereum private key: %w", err)
+	}
+
+	return &signingKey{ethereum: privateKey}, nil
+}
+
+// signConfig writes the detached signature of the config file at path next to it.
+func signConfig(path string, key *signingKey) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	canonical, err := canonicalJSON(content)
+	if err != nil {
+		return err
+	}
+
+	signature, err := key.sign(canonical)
+	if err != nil {
+		return fmt.Errorf("error signing config: %w", err)
+	}
+
+	encoded, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling signature: %w", err)
+	}
+
+	return writeFile(path+signatureSuffix, append(encoded, '\n'))
+}
+
+// readSignature reads the detached signature at path.
+func readSignature(path string) (*detachedSignature, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signature: %w", err)
+	}
+
+	var signature detachedSignature
+	if err := json.Unmarshal(content, &signature); err != nil {
+		return nil, fmt.Errorf("error parsing signature: %w", err)
+	}
+
+	return &signature, nil
+}
+
+// canonicalJSON returns content as compact JSON with the keys of objects sorted, so that signatures don't depend
+// on how the file is formatted. Numbers are kept as written.
+func canonicalJSON(content []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling canonical config: %w", err)
+	}
+
+	return canonical, nil
+}
+
+// ethereumMessageHash returns the hash personal_sign signs for message, as defined by EIP-191.
+func ethereumMessageHash(message []byte) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+}
+
+// sign signs canonical with k.
+func (k *signingKey) sign(canonical []byte) (*detachedSignature, error) {
+	if k.ed25519 != nil {
+		return &detachedSignature{
+			Algorithm: signatureEd25519,
+			PublicKey: hexutil.Encode(k.ed25519.Public().(ed25519.PublicKey)),
+			Signature: hexutil.Encode(ed25519.Sign(k.ed25519, canonical)),
+		}, nil
+	}
+
+	signature, err := crypto.Sign(ethereumMessageHash(canonical), k.ethereum)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wallets expect recovery IDs of 27 or 28, as in Ethereum transactions before EIP-155
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return &detachedSignature{
+		Algorithm: signatureEthereum,
+		Address:   crypto.PubkeyToAddress(k.ethereum.PublicKey).Hex(),
+		Signature: hexutil.Encode(signature),
+	}, nil
+}
+
+// verify checks that s is a signature of canonical by the key it names.
+func (s *detachedSignature) verify(canonical []byte) error {
+	signature, err := hexutil.Decode(s.Signature)
+	if err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+
+	switch s.Algorithm {
+	case signatureEd25519:
+		publicKey, err := hexutil.Decode(s.PublicKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("error parsing signature: invalid public key %q", s.PublicKey)
+		}
+
+		if !ed25519.Verify(publicKey, canonical, signature) {
+			return errors.New("signature doesn't match the config")
+		}
+	case signatureEthereum:
+		if len(signature) != crypto.SignatureLength {
+			return fmt.Errorf("error parsing signature: expected %d bytes, got %d", crypto.SignatureLength, len(signature))
+		}
+
+		if signature[crypto.RecoveryIDOffset] >= 27 {
+			signature[crypto.RecoveryIDOffset] -= 27
+		}
+
+		publicKey, err := crypto.SigToPub(ethereumMessageHash(canonical), signature)
+		if err != nil || crypto.PubkeyToAddress(*publicKey) != common.HexToAddress(s.Address) {
+			return errors.New("signature doesn't match the config")
+		}
+	default:
+		return fmt.Errorf("error parsing signature: unsupported algorithm %q", s.Algorithm)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var timestampCmd = &cobra.Command{
+	Use:   "timestamp",
+	Short: "Print the timestamp of a block of a network",
+	Long: `Print the timestamp of a block of a network, as Unix seconds and as an RFC3339 date.
+Blocks are slots on Solana and the beacon chain, as in start blocks.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("network")
+		block, _ := cmd.Flags().GetInt64("block")
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		network, err := lookupNetwork(config, name)
+		if err != nil {
+			return err
+		}
+
+		source, conn, err := dialNetwork(cmd.Context(), network)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if err := verifyChainID(cmd.Context(), network, source); err != nil {
+			return err
+		}
+
+		timestamp, err := source.BlockTimestamp(cmd.Context(), block)
+		if err != nil {
+			return fmt.Errorf("error getting timestamp of block %d: %w", block, err)
+		}
+
+		fmt.Printf("%d\t%d\t%s\n", block, timestamp, time.Unix(timestamp, 0).UTC().Format(time.RFC3339))
+
+		return nil
+	},
+}
+
+func init() {
+	timestampCmd.Flags().String("network", "", "name of the network, as in the config or the networks of the profile")
+	timestampCmd.Flags().Int64("block", 0, "number of the block")
+	_ = timestampCmd.MarkFlagRequired("network")
+	_ = timestampCmd.MarkFlagRequired("block")
+
+	rootCmd.AddCommand(timestampCmd)
+}
+
+// parseTimestamp parses a target timestamp given as Unix seconds, as an RFC3339 date or as a time before now
+// like "now-30d", and rejects values that are not positive or lie in the future.
+func parseTimestamp(value string) (int64, error) {
+	var timestamp int64
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		timestamp = seconds
+	} else if strings.HasPrefix(value, "now") {
+		ago, err := parseAgo(strings.TrimPrefix(value, "now"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q: %v", value, err)
+		}
+		timestamp = time.Now().Add(-ago).Unix()
+	} else {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q: expected Unix seconds, RFC3339 date or now-<duration>", value)
+		}
+		timestamp = date.Unix()
+	}
+
+	if timestamp <= 0 {
+		return 0, fmt.Errorf("invalid timestamp %q: must be after the Unix epoch", value)
+	}
+
+	if timestamp > time.Now().Unix() {
+		return 0, fmt.Errorf("invalid timestamp %q: %s is in the future", value, time.Unix(timestamp, 0).UTC().Format(time.RFC3339))
+	}
+
+	return timestamp, nil
+}
+
+// parseAgo parses the offset of a relative timestamp, like "-30d" or "-12h", into the time before now it stands for.
+// Days are accepted on top of the units of time.ParseDuration.
+func parseAgo(offset string) (time.Duration, error) {
+	if offset == "" {
+		return 0, nil
+	}
+
+	if !strings.HasPrefix(offset, "-") {
+		return 0, fmt.Errorf("expected now or now-<duration>, like now-30d")
+	}
+
+	offset = strings.TrimPrefix(offset, "-")
+
+	if days, ok := strings.CutSuffix(offset, "d"); ok {
+		count, err := strconv.ParseFloat(days, 64)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", days)
+		}
+
+		return time.Duration(count * float64(24*time.Hour)), nil
+	}
+
+	ago, err := time.ParseDuration(offset)
+	if err != nil || ago < 0 {
+		return 0, fmt.Errorf("invalid duration %q", offset)
+	}
+
+	return ago, nil
+}
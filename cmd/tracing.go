@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingShutdownTimeout bounds the time spent exporting the last spans on exit.
+const tracingShutdownTimeout = 5 * time.Second
+
+var (
+	// otlpEndpoint is the OTLP/HTTP endpoint spans are exported to, if tracing is enabled with --otlp-endpoint.
+	otlpEndpoint string
+	// shutdownTracing exports the remaining spans and stops tracing, once tracing is set up.
+	shutdownTracing = func(context.Context) error { return nil }
+	// tracer traces resolution runs, as a span per run with a child span per network.
+	tracer = otel.Tracer("get-node-start-block/cmd")
+)
+
+// setupTracing exports spans over OTLP/HTTP to --otlp-endpoint, or to the endpoint set by the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT variables. Tracing stays off if none is set.
+// The exporter reads the other OTEL_EXPORTER_OTLP_* variables, like the headers authenticating with the collector.
+func setupTracing(ctx context.Context) error {
+	if otlpEndpoint == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return nil
+	}
+
+	var options []otlptracehttp.Option
+	if otlpEndpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(otlpEndpoint))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return fmt.Errorf("error setting up tracing: %w", err)
+	}
+
+	toolVersion, _ := buildVersion()
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "get-node-start-block"),
+			attribute.String("service.version", toolVersion),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	shutdownTracing = provider.Shutdown
+
+	slog.Debug("Tracing enabled")
+
+	return nil
+}
+
+// stopTracing exports the remaining spans, if tracing is set up.
+func stopTracing() {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Error exporting spans", "error", err)
+	}
+}
+
+// endSpan marks span as failed with err, if not nil, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"get-node-start-block/pkg/objectstore"
+)
+
+// latestKey is the key prefix under which the most recent upload is kept, next to the versioned ones.
+const latestKey = "latest"
+
+// newObjectStore returns the store of the bucket of location: with the default AWS config for s3://, reading
+// credentials from the environment, shared config files or the role of the instance, and with HMAC keys in
+// GCS_ACCESS_KEY_ID and GCS_SECRET_ACCESS_KEY for gs://.
+func newObjectStore(ctx context.Context, location objectstore.Location) (*objectstore.Store, error) {
+	if location.Scheme != "gs" {
+		return objectstore.NewS3(ctx, location.Bucket)
+	}
+
+	keys := objectstore.Credentials{
+		AccessKeyID:     os.Getenv("GCS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("GCS_SECRET_ACCESS_KEY"),
+	}
+	if keys.AccessKeyID == "" || keys.SecretAccessKey == "" {
+		return nil, fmt.Errorf("no credentials for gs://%s in the environment", location.Bucket)
+	}
+
+	return objectstore.NewGCS(location.Bucket, keys), nil
+}
+
+// uploadDestination is where --upload uploads the written config.
+type uploadDestination struct {
+	Location objectstore.Location
+	Store    *objectstore.Store
+}
+
+// newUploadDestination returns the destination of the location given to --upload.
+func newUploadDestination(ctx context.Context, rawURL string) (*uploadDestination, error) {
+	location, err := objectstore.ParseLocation(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := newObjectStore(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploadDestination{Location: location, Store: store}, nil
+}
+
+// uploadConfig uploads the config written at localPath in format under <prefix>/<target>/<name> of destination,
+// and again under <prefix>/latest/<name> so that deployments can always fetch the newest params at the same key.
+func uploadConfig(ctx context.Context, destination *uploadDestination, localPath, format string, targetTimestamp int64) error {
+	location := destination.Location
+
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", localPath, err)
+	}
+
+	name := filepath.Base(localPath)
+
+	// The versioned key goes first, so the latest one never points at params that weren't kept
+	for _, version := range []string{strconv.FormatInt(targetTimestamp, 10), latestKey} {
+		key := location.Key(version + "/" + name)
+
+		if err := destination.Store.Put(ctx, key, content, contentType(format, localPath)); err != nil {
+			return fmt.Errorf("error uploading config: %w", err)
+		}
+
+		slog.Info("Config uploaded", "location", fmt.Sprintf("%s://%s/%s", location.Scheme, location.Bucket, key))
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the start blocks in the config against the chains",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timestampFlag, _ := cmd.Flags().GetString("timestamp")
+		tolerance, _ := cmd.Flags().GetDuration("tolerance")
+
+		targetTimestamp, err := parseTimestamp(timestampFlag)
+		if err != nil {
+			return fmt.Errorf("error parsing target timestamp: %w", err)
+		}
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		var failed, flagged int
+
+		networkList, err := selectedNetworks(cmd, config)
+		if err != nil {
+			return err
+		}
+
+		for _, network := range networkList {
+			startBlock, ok := config.NetworkStartBlock[network.Name]
+			if !ok {
+				continue
+			}
+			block := startBlock.Block
+
+			logger := slog.With("network", network.Name, "block", block)
+
+			if len(network.URLs) == 0 {
+				if !network.Required {
+					logger.Warn("Network not configured, leaving it out", "reason", notConfiguredError(network))
+					continue
+				}
+
+				logger.Error("Error verifying network", "error", notConfiguredError(network))
+				failed++
+
+				continue
+			}
+
+			result, err := verifyNetwork(cmd.Context(), network, block, targetTimestamp, tolerance)
+			if err != nil {
+				logger.Error("Error verifying network", "error", err)
+				failed++
+				continue
+			}
+
+			logger = logger.With("status", result.Status, "head", result.Head)
+			if result.Status != statusBeyondHead && result.Status != statusBeforeMinimum {
+				logger = logger.With(
+					"block_time", time.Unix(result.Timestamp, 0).UTC().Format(time.RFC3339),
+					"difference", time.Duration(result.Timestamp-targetTimestamp)*time.Second)
+			}
+
+			if result.Status != statusOK {
+				logger.Warn("Start block flagged")
+				flagged++
+				continue
+			}
+
+			logger.Info("Start block verified")
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to verify %d networks, %d more flagged", failed, flagged)
+		}
+
+		if flagged > 0 {
+			return fmt.Errorf("flagged %d networks", flagged)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().String("timestamp", defaultTimestamp, "target time as Unix seconds, RFC3339 date (e.g. 2024-06-01T00:00:00Z) or time before now (e.g. now-30d)")
+	verifyCmd.Flags().Duration("tolerance", time.Hour, "maximum distance between a configured block and the target before it is flagged as stale or in the future")
+	addSelectFlags(verifyCmd)
+
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// Statuses of a verified start block.
+const (
+	statusOK = "ok"
+	// statusStale marks blocks too long before the target, which make the node index more than needed.
+	statusStale = "stale"
+	// statusFuture marks blocks too long after the target, which make the node miss events.
+	statusFuture = "in the future"
+	// statusBeyondHead marks blocks the chain hasn't produced yet.
+	statusBeyondHead = "beyond chain head"
+	// statusBeforeMinimum marks blocks from a history the node can't index, such as before a regenesis.
+	statusBeforeMinimum = "before minimum block"
+)
+
+// verification is the outcome of verifying the start block of a network.
+type verification struct {
+	Timestamp int64
+	Head      int64
+	Status    string
+}
+
+// verifyNetwork checks the given start block of network against the chain head and targetTimestamp.
+func verifyNetwork(ctx context.Context, network Network, block, targetTimestamp int64, tolerance time.Duration) (verification, error) {
+	source, conn, err := dialNetwork(ctx, network)
+	if err != nil {
+		return verification{}, err
+	}
+	defer conn.Close()
+
+	if err := verifyChainID(ctx, network, source); err != nil {
+		return verification{}, err
+	}
+
+	// Searching for the current time ends at the chain head straight away
+	head, err := source.FindBlockByTimestamp(ctx, time.Now().Unix())
+	if err != nil {
+		return verification{}, fmt.Errorf("error getting chain head: %v", err)
+	}
+
+	result := verification{Head: head.Number}
+
+	if block > head.Number {
+		result.Status = statusBeyondHead
+		return result, nil
+	}
+
+	if block < network.MinBlock {
+		result.Status = statusBeforeMinimum
+		return result, nil
+	}
+
+	if result.Timestamp, err = source.BlockTimestamp(ctx, block); err != nil {
+		return verification{}, err
+	}
+
+	switch deviation := time.Duration(result.Timestamp-targetTimestamp) * time.Second; {
+	case deviation < -tolerance:
+		result.Status = statusStale
+	case deviation > tolerance:
+		result.Status = statusFuture
+	default:
+		result.Status = statusOK
+	}
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"runtime/debug"
+)
+
+// version is the version of the tool, set at build time with -ldflags "-X get-node-start-block/cmd.version=v1.2.3".
+// Builds without it fall back to the module version Go recorded, if any.
+var version = ""
+
+// buildVersion returns the version of the tool and the git commit it was built from, empty if unknown.
+// Commits of builds with uncommitted changes are suffixed with "-dirty".
+func buildVersion() (string, string) {
+	toolVersion, commit := version, ""
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return toolVersion, commit
+	}
+
+	if toolVersion == "" && info.Main.Version != "(devel)" {
+		toolVersion = info.Main.Version
+	}
+
+	var dirty bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			commit = setting.Value
+		case "vcs.modified":
+			dirty = setting.Value == "true"
+		}
+	}
+
+	if commit != "" && dirty {
+		commit += "-dirty"
+	}
+
+	return toolVersion, commit
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	// watchPollInterval is how often watched files are checked for changes.
+	watchPollInterval = time.Second
+	// dotenvPath is the .env file read on startup, and again in watch mode when it changes.
+	dotenvPath = ".env"
+)
+
+var (
+	// inheritedEnv holds the environment variables set before the .env file was loaded, which it never overrides.
+	inheritedEnv = make(map[string]bool)
+	// dotenvKeys holds the environment variables set from the .env file.
+	dotenvKeys = make(map[string]bool)
+)
+
+// loadDotenv sets the environment variables of the .env file, except the ones inherited from the environment.
+// Variables dropped from the file since it was last loaded are unset.
+func loadDotenv() error {
+	if len(inheritedEnv) == 0 {
+		for _, key := range environmentKeys() {
+			inheritedEnv[key] = true
+		}
+	}
+
+	variables, err := godotenv.Read(dotenvPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for key := range dotenvKeys {
+		if _, ok := variables[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+
+	for key, value := range variables {
+		if inheritedEnv[key] {
+			continue
+		}
+
+		os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+
+	return err
+}
+
+// environmentKeys returns the names of the variables set in the environment.
+func environmentKeys() []string {
+	environment := os.Environ()
+
+	keys := make([]string, 0, len(environment))
+	for _, variable := range environment {
+		if key, _, ok := strings.Cut(variable, "="); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// runWatch runs run straight away and again whenever one of the files at paths changes, until ctx is done.
+// Failed runs are logged and wait for the next change. Changes to the .env file are loaded before running.
+func runWatch(ctx context.Context, paths []string, run func(ctx context.Context) error) error {
+	for {
+		if err := run(ctx); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+
+			slog.Error("Error resolving start blocks, waiting for the next change", "error", err)
+		}
+
+		// Taken after the run, so that the config it wrote doesn't count as a change
+		fingerprints := fingerprintFiles(paths)
+
+		slog.Info("Watching for changes", "files", paths)
+
+		changed, err := waitForChange(ctx, paths, fingerprints)
+		if err != nil {
+			slog.Info("Stopping watch")
+			return nil
+		}
+
+		slog.Info("File changed, resolving again", "path", changed)
+
+		if changed == dotenvPath {
+			if err := loadDotenv(); err != nil {
+				slog.Warn("Error loading .env file", "error", err)
+			}
+		}
+	}
+}
+
+// waitForChange polls the files at paths until one no longer matches fingerprints, and returns its path.
+// It returns the error of ctx once it is done.
+func waitForChange(ctx context.Context, paths []string, fingerprints map[string][sha256.Size]byte) (string, error) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+
+		for path, fingerprint := range fingerprintFiles(paths) {
+			if fingerprint != fingerprints[path] {
+				return path, nil
+			}
+		}
+	}
+}
+
+// fingerprintFiles hashes the content of the files at paths. Missing files hash as empty,
+// so that creating or removing them is a change.
+func fingerprintFiles(paths []string) map[string][sha256.Size]byte {
+	fingerprints := make(map[string][sha256.Size]byte, len(paths))
+
+	for _, path := range paths {
+		content, _ := os.ReadFile(path)
+		fingerprints[path] = sha256.Sum256(content)
+	}
+
+	return fingerprints
+}
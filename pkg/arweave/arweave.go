@@ -0,0 +1,341 @@
+// Package arweave signs and submits Arweave data transactions with a JWK wallet.
+package arweave
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxChunkSize is the size of the chunks data is split into for its data root.
+	maxChunkSize = 256 * 1024
+	// minChunkSize is the smallest size of a last chunk that isn't balanced with the chunk before it.
+	minChunkSize = 32 * 1024
+)
+
+// Tag is a name and value attached to a transaction, like its Content-Type.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Wallet is an Arweave wallet, whose RSA key signs transactions.
+type Wallet struct {
+	key *rsa.PrivateKey
+}
+
+// LoadWallet reads the JWK wallet file at path, as exported by Arweave wallets.
+func LoadWallet(path string) (*Wallet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading wallet: %w", err)
+	}
+
+	var jwk struct {
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		D   string `json:"d"`
+		P   string `json:"p"`
+		Q   string `json:"q"`
+	}
+	if err := json.Unmarshal(content, &jwk); err != nil {
+		return nil, fmt.Errorf("error parsing wallet: %w", err)
+	}
+
+	if jwk.Kty != "RSA" {
+		return nil, fmt.Errorf("error parsing wallet: unsupported key type %q", jwk.Kty)
+	}
+
+	var numbers [5]*big.Int
+	for index, field := range []string{jwk.N, jwk.E, jwk.D, jwk.P, jwk.Q} {
+		decoded, err := base64.RawURLEncoding.DecodeString(field)
+		if err != nil || len(decoded) == 0 {
+			return nil, errors.New("error parsing wallet: missing or invalid RSA parameters")
+		}
+
+		numbers[index] = new(big.Int).SetBytes(decoded)
+	}
+
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: numbers[0], E: int(numbers[1].Int64())},
+		D:         numbers[2],
+		Primes:    []*big.Int{numbers[3], numbers[4]},
+	}
+
+	if err := key.Validate(); err != nil {
+		return nil, fmt.Errorf("error parsing wallet: %w", err)
+	}
+
+	key.Precompute()
+
+	return &Wallet{key: key}, nil
+}
+
+// Client submits transactions to an Arweave gateway.
+type Client struct {
+	gateway    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client for the gateway at the given URL, like https://arweave.net.
+func NewClient(gateway string) *Client {
+	return &Client{gateway: strings.TrimSuffix(gateway, "/"), httpClient: http.DefaultClient}
+}
+
+// transaction is a format 2 Arweave transaction, as posted to gateways.
+type transaction struct {
+	Format    int    `json:"format"`
+	ID        string `json:"id"`
+	LastTx    string `json:"last_tx"`
+	Owner     string `json:"owner"`
+	Tags      []tag  `json:"tags"`
+	Target    string `json:"target"`
+	Quantity  string `json:"quantity"`
+	Data      string `json:"data"`
+	DataSize  string `json:"data_size"`
+	DataRoot  string `json:"data_root"`
+	Reward    string `json:"reward"`
+	Signature string `json:"signature"`
+}
+
+type tag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Upload stores data on Arweave in a transaction signed by wallet, paying the fee the gateway asks for,
+// and returns the transaction ID. Data is posted inline, which gateways accept for small files like configs.
+func (c *Client) Upload(ctx context.Context, wallet *Wallet, data []byte, tags []Tag) (string, error) {
+	anchor, err := c.getText(ctx, "tx_anchor")
+	if err != nil {
+		return "", fmt.Errorf("error getting transaction anchor: %w", err)
+	}
+
+	reward, err := c.getText(ctx, "price/"+strconv.Itoa(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("error getting upload price: %w", err)
+	}
+
+	tx := transaction{
+		Format:   2,
+		LastTx:   anchor,
+		Owner:    encode(wallet.key.N.Bytes()),
+		Quantity: "0",
+		Data:     encode(data),
+		DataSize: strconv.Itoa(len(data)),
+		DataRoot: encode(dataRoot(data)),
+		Reward:   reward,
+		Tags:     make([]tag, 0, len(tags)),
+	}
+
+	for _, t := range tags {
+		tx.Tags = append(tx.Tags, tag{Name: encode([]byte(t.Name)), Value: encode([]byte(t.Value))})
+	}
+
+	digest := sha256.Sum256(signatureData(tx))
+
+	signature, err := rsa.SignPSS(rand.Reader, wallet.key, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: 32})
+	if err != nil {
+		return "", fmt.Errorf("error signing transaction: %w", err)
+	}
+
+	id := sha256.Sum256(signature)
+	tx.Signature, tx.ID = encode(signature), encode(id[:])
+
+	body, err := json.Marshal(tx)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling transaction: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.gateway+"/tx", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error posting transaction: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+		return "", fmt.Errorf("error posting transaction: unexpected status code %d: %s", response.StatusCode, content)
+	}
+
+	return tx.ID, nil
+}
+
+// getText sends a GET request for path to the gateway and returns the plain text answer.
+func (c *Client) getText(ctx context.Context, path string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.gateway+"/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d: %s", response.StatusCode, content)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// signatureData returns the deep hash of the fields of tx covered by its signature.
+func signatureData(tx transaction) []byte {
+	tags := make([]interface{}, 0, len(tx.Tags))
+	for _, t := range tx.Tags {
+		tags = append(tags, []interface{}{decode(t.Name), decode(t.Value)})
+	}
+
+	return deepHash([]interface{}{
+		[]byte(strconv.Itoa(tx.Format)),
+		decode(tx.Owner),
+		decode(tx.Target),
+		[]byte(tx.Quantity),
+		[]byte(tx.Reward),
+		decode(tx.LastTx),
+		tags,
+		[]byte(tx.DataSize),
+		decode(tx.DataRoot),
+	})
+}
+
+// deepHash hashes a tree of byte slices and lists of them with SHA-384, the way Arweave signs transactions.
+func deepHash(item interface{}) []byte {
+	if list, ok := item.([]interface{}); ok {
+		accumulator := sha384([]byte("list" + strconv.Itoa(len(list))))
+		for _, child := range list {
+			accumulator = sha384(accumulator, deepHash(child))
+		}
+
+		return accumulator
+	}
+
+	blob := item.([]byte)
+
+	return sha384(sha384([]byte("blob"+strconv.Itoa(len(blob)))), sha384(blob))
+}
+
+// dataRoot returns the root of the Merkle tree of the chunks of data, which commits a transaction to its data.
+// Transactions without data have an empty data root.
+func dataRoot(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	type node struct {
+		id       []byte
+		maxRange int
+	}
+
+	var nodes []node
+
+	// Chunks are split like the reference client does: a last chunk too small to stand alone is balanced with the
+	// chunk before it, and data filling whole chunks ends with an empty one.
+	offset, rest := 0, data
+	for {
+		size := len(rest)
+		if size >= maxChunkSize {
+			size = maxChunkSize
+			if next := len(rest) - maxChunkSize; next > 0 && next < minChunkSize {
+				size = (len(rest) + 1) / 2
+			}
+		}
+
+		chunkHash := sha256.Sum256(rest[:size])
+		offset += size
+		nodes = append(nodes, node{id: sha256Of(sha256Of(chunkHash[:]), sha256Of(noteBytes(offset))), maxRange: offset})
+
+		if len(rest) < maxChunkSize {
+			break
+		}
+
+		rest = rest[size:]
+	}
+
+	for len(nodes) > 1 {
+		var parents []node
+
+		for index := 0; index < len(nodes); index += 2 {
+			if index+1 == len(nodes) {
+				parents = append(parents, nodes[index])
+				continue
+			}
+
+			left, right := nodes[index], nodes[index+1]
+			parents = append(parents, node{
+				id:       sha256Of(sha256Of(left.id), sha256Of(right.id), sha256Of(noteBytes(left.maxRange))),
+				maxRange: right.maxRange,
+			})
+		}
+
+		nodes = parents
+	}
+
+	return nodes[0].id
+}
+
+// noteBytes encodes an offset as the 32-byte big-endian number of Arweave Merkle trees.
+func noteBytes(note int) []byte {
+	buffer := make([]byte, 32)
+	new(big.Int).SetInt64(int64(note)).FillBytes(buffer)
+
+	return buffer
+}
+
+func sha256Of(parts ...[]byte) []byte {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+	}
+
+	return hash.Sum(nil)
+}
+
+func sha384(parts ...[]byte) []byte {
+	hash := sha512.New384()
+	for _, part := range parts {
+		hash.Write(part)
+	}
+
+	return hash.Sum(nil)
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(data string) []byte {
+	decoded, _ := base64.RawURLEncoding.DecodeString(data)
+	return decoded
+}
@@ -0,0 +1,196 @@
+package arweave
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The expected hashes of these tests were computed with the deepHash and merkle modules of arweave-js, run on the
+// crypto of Node.js rather than on this package.
+
+// pattern returns size bytes counting up modulo mod, so that chunks of the same size don't hash alike.
+func pattern(size, mod int) []byte {
+	data := make([]byte, size)
+	for index := range data {
+		data[index] = byte(index % mod)
+	}
+
+	return data
+}
+
+func TestDeepHash(t *testing.T) {
+	tests := []struct {
+		name string
+		item interface{}
+		want string
+	}{
+		{
+			name: "blob",
+			item: []byte("arweave"),
+			want: "42338096889fc23c0ee68268dfa6361fff3cc62d2577f077979befe9ee15bb8200bef0a4d26331b7d2c6460ecea43697",
+		},
+		{
+			name: "empty list",
+			item: []interface{}{},
+			want: "a69e7d37fdc7f040a9ec16aae84de24fab4a653dac4de0bd247e36bab9fe45d9289c5a04a893c95285812f5cefc9707a",
+		},
+		{
+			name: "nested lists",
+			item: []interface{}{[]byte("a"), []interface{}{[]byte("b"), []byte{}}, []interface{}{[]interface{}{}}, []byte("rss3")},
+			want: "70777f5228c0675feac73d6a1ba333a12b6d80efc31051c5804eb439fd51ab5fbd4d96a5eb95670aa4e672a83b02d298",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hex.EncodeToString(deepHash(test.item)); got != test.want {
+				t.Errorf("deepHash() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDataRoot(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want string
+	}{
+		{name: "single byte", size: 1, want: "1edff26615c60435197cbbf8383eb07bb164ae37290e918fc1064466010955f9"},
+		{name: "one whole chunk", size: maxChunkSize, want: "82dcbb281d9b68b169ece1b1b95db005e5f7362a694b5b4d56530e66bc88ab9a"},
+		{name: "three chunks", size: 600_000, want: "3b9a56afd7a67130990a883698bb31d6820b3392e15addf5c73ff5eaab5e0e8c"},
+		{name: "balanced last chunks", size: 2*maxChunkSize + 1000, want: "bcca8e769831acf5a395d699b0cdcf2040f2c93472cbf844cbe00d77312c2682"},
+		{name: "two whole chunks", size: 2 * maxChunkSize, want: "1f66cd9af7edcc094841755809eb45b09f5fd2ea02e02d856df8af653452caa2"},
+		{name: "no data", size: 0, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hex.EncodeToString(dataRoot(pattern(test.size, 251))); got != test.want {
+				t.Errorf("dataRoot() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSignatureData(t *testing.T) {
+	data := []byte(`{"network_start_block":{"ethereum":20000000}}` + "\n")
+
+	tx := transaction{
+		Format:   2,
+		Owner:    encode(pattern(512, 256)),
+		Quantity: "0",
+		Reward:   "1234",
+		LastTx:   encode(pattern(48, 7)),
+		Tags: []tag{
+			{Name: encode([]byte("Content-Type")), Value: encode([]byte("application/json"))},
+			{Name: encode([]byte("App-Name")), Value: encode([]byte("get-node-start-block"))},
+		},
+		DataSize: "46",
+		DataRoot: encode(dataRoot(data)),
+	}
+
+	if want := "f3e9566b784167945c4253d67c778cbe4314856a09968aaf8e8688ac31a26380"; hex.EncodeToString(decode(tx.DataRoot)) != want {
+		t.Errorf("dataRoot() = %x, want %s", decode(tx.DataRoot), want)
+	}
+
+	want := "c66f22c467557720aebc7cc171f3fc500b2fb9add0849385f3e141ee52394bae75b04b7b6d16066c20e7a1c32f6b90ce"
+	if got := hex.EncodeToString(signatureData(tx)); got != want {
+		t.Errorf("signatureData() = %s, want %s", got, want)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	wallet, err := LoadWallet(writeWallet(t, key))
+	if err != nil {
+		t.Fatalf("LoadWallet() error = %v", err)
+	}
+
+	anchor := encode(pattern(48, 7))
+	data := []byte(`{"network_start_block":{"ethereum":20000000}}` + "\n")
+
+	var posted transaction
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tx_anchor":
+			_, _ = w.Write([]byte(anchor))
+		case "/price/46":
+			_, _ = w.Write([]byte("1234"))
+		case "/tx":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gateway.Close()
+
+	id, err := NewClient(gateway.URL+"/").Upload(context.Background(), wallet, data, []Tag{{Name: "Content-Type", Value: "application/json"}})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if posted.LastTx != anchor || posted.Reward != "1234" || posted.DataSize != "46" || string(decode(posted.Data)) != string(data) {
+		t.Errorf("Upload() posted %+v, want the anchor, price and data of the gateway", posted)
+	}
+
+	if string(decode(posted.Tags[0].Name)) != "Content-Type" || string(decode(posted.Tags[0].Value)) != "application/json" {
+		t.Errorf("Upload() posted tags %+v, want Content-Type: application/json", posted.Tags)
+	}
+
+	signature := decode(posted.Signature)
+	digest := sha256.Sum256(signatureData(posted))
+
+	if err := rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, digest[:], signature, &rsa.PSSOptions{SaltLength: 32}); err != nil {
+		t.Errorf("Upload() posted a signature that doesn't verify: %v", err)
+	}
+
+	if hash := sha256.Sum256(signature); id != encode(hash[:]) || posted.ID != id {
+		t.Errorf("Upload() = %s, posted %s, want the hash of the signature %s", id, posted.ID, encode(hash[:]))
+	}
+}
+
+// writeWallet writes key as a JWK wallet file, returning its path.
+func writeWallet(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	field := func(number *big.Int) string { return base64.RawURLEncoding.EncodeToString(number.Bytes()) }
+
+	content, err := json.Marshal(map[string]string{
+		"kty": "RSA",
+		"n":   field(key.N),
+		"e":   field(big.NewInt(int64(key.E))),
+		"d":   field(key.D),
+		"p":   field(key.Primes[0]),
+		"q":   field(key.Primes[1]),
+	})
+	if err != nil {
+		t.Fatalf("error marshaling wallet: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("error writing wallet: %v", err)
+	}
+
+	return path
+}
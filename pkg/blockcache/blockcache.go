@@ -0,0 +1,103 @@
+// Package blockcache keeps block timestamps on disk, so that repeated searches don't fetch the same blocks again.
+package blockcache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"get-node-start-block/pkg/blockfinder"
+)
+
+// Cache is a bbolt database holding one bucket of block timestamps per network.
+type Cache struct {
+	db *bbolt.DB
+}
+
+// Open opens the cache at path, creating it and its directory if needed.
+func Open(path string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	// Another run holding the cache shouldn't block this one for long
+	db, err := bbolt.Open(path, 0644, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open cache: %w", err)
+	}
+
+	return &Cache{db: db}, nil
+}
+
+// Close closes the cache.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Network returns the cache of the named network.
+func (c *Cache) Network(name string) *NetworkCache {
+	return &NetworkCache{db: c.db, name: name}
+}
+
+// NetworkCache is the part of a Cache holding the block timestamps of a single network.
+type NetworkCache struct {
+	db   *bbolt.DB
+	name string
+}
+
+var _ blockfinder.TimestampCache = (*NetworkCache)(nil)
+
+// Timestamp returns the cached timestamp of the block with the given number, if any.
+func (c *NetworkCache) Timestamp(number int64) (int64, bool) {
+	var (
+		timestamp int64
+		found     bool
+	)
+
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(c.name))
+		if bucket == nil {
+			return nil
+		}
+
+		if value := bucket.Get(encode(number)); len(value) == 8 {
+			timestamp, found = int64(binary.BigEndian.Uint64(value)), true
+		}
+
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Error reading block from cache", "network", c.name, "block", number, "error", err)
+	}
+
+	return timestamp, found
+}
+
+// SetTimestamp caches the timestamp of the block with the given number. Failures are only logged,
+// as the cache merely saves requests.
+func (c *NetworkCache) SetTimestamp(number, timestamp int64) {
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(c.name))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(encode(number), encode(timestamp))
+	})
+	if err != nil {
+		slog.Warn("Error writing block to cache", "network", c.name, "block", number, "error", err)
+	}
+}
+
+// encode encodes value big-endian, which keeps the keys of a bucket in block order.
+func encode(value int64) []byte {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, uint64(value))
+
+	return encoded
+}
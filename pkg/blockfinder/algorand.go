@@ -0,0 +1,56 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// AlgorandFinder reads Algorand rounds through the REST API of algod. Nodes only keep the last thousand rounds
+// unless they are archival, which searching further back needs. It is searched by a ChainFinder.
+type AlgorandFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Chain     = (*AlgorandFinder)(nil)
+	_ Finalizer = (*AlgorandFinder)(nil)
+)
+
+// NewAlgorandFinder creates an AlgorandFinder using the given HTTP client.
+func NewAlgorandFinder(client HTTPClient) *AlgorandFinder {
+	return &AlgorandFinder{client: client}
+}
+
+// LatestHeight returns the last round the node knows of.
+func (f *AlgorandFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var status struct {
+		LastRound int64 `json:"last-round"`
+	}
+	if err := f.client.GetJSON(ctx, "v2/status", &status); err != nil {
+		return 0, fmt.Errorf("error getting status: %v", err)
+	}
+
+	return status.LastRound, nil
+}
+
+// FinalizedHeight implements Finalizer. Algorand rounds are final as soon as they are certified,
+// so this is the last round.
+func (f *AlgorandFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.LatestHeight(ctx)
+}
+
+// BlockTimestamp returns the timestamp of the block of the given round.
+func (f *AlgorandFinder) BlockTimestamp(ctx context.Context, round int64) (int64, error) {
+	var block struct {
+		Block struct {
+			Timestamp int64 `json:"ts"`
+		} `json:"block"`
+	}
+
+	// Blocks are encoded as msgpack unless JSON is asked for
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("v2/blocks/%d?format=json", round), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", round, err)
+	}
+
+	return block.Block.Timestamp, nil
+}
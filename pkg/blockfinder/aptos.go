@@ -0,0 +1,155 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// AptosFinder finds ledger versions on Aptos through the REST API of its fullnodes.
+//
+// Aptos indexers start from a ledger version, the sequence number of transactions, rather than a block height.
+// Blocks are searched by height, and the returned BlockRef holds the first version of the block found.
+// Heights passed to BlockTimestamp and returned by LatestHeight are ledger versions too.
+type AptosFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder          = (*AptosFinder)(nil)
+	_ Chain           = (*AptosFinder)(nil)
+	_ ChainIdentifier = (*AptosFinder)(nil)
+)
+
+// NewAptosFinder creates an AptosFinder using the given HTTP client.
+func NewAptosFinder(client HTTPClient) *AptosFinder {
+	return &AptosFinder{client: client}
+}
+
+// aptosLedgerInfo is the ledger information returned by the root of the API. Numbers are encoded as strings.
+type aptosLedgerInfo struct {
+	ChainID           int64  `json:"chain_id"`
+	LedgerVersion     string `json:"ledger_version"`
+	BlockHeight       string `json:"block_height"`
+	OldestBlockHeight string `json:"oldest_block_height"`
+}
+
+// aptosBlock is a block without its transactions. Timestamps are in microseconds.
+type aptosBlock struct {
+	BlockHeight    string `json:"block_height"`
+	BlockTimestamp string `json:"block_timestamp"`
+	FirstVersion   string `json:"first_version"`
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *AptosFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	info, err := f.ledgerInfo(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	high, err := strconv.ParseInt(info.BlockHeight, 10, 64)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing block height: %v", err)
+	}
+
+	// Pruned nodes only serve blocks from their oldest height on, and the genesis block has no timestamp
+	low, err := strconv.ParseInt(info.OldestBlockHeight, 10, 64)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing oldest block height: %v", err)
+	}
+
+	found, err := searchRange(ctx, max(low, 1), high, timestamp, f.cached(f.heightTimestamp))
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	block, err := f.blockByHeight(ctx, found.Number)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	version, err := strconv.ParseInt(block.FirstVersion, 10, 64)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing first version of block %d: %v", found.Number, err)
+	}
+
+	return BlockRef{Number: version, Timestamp: found.Timestamp}, nil
+}
+
+// LatestHeight returns the latest ledger version.
+func (f *AptosFinder) LatestHeight(ctx context.Context) (int64, error) {
+	info, err := f.ledgerInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.ParseInt(info.LedgerVersion, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing ledger version: %v", err)
+	}
+
+	return version, nil
+}
+
+// ChainID implements ChainIdentifier.
+func (f *AptosFinder) ChainID(ctx context.Context) (int64, error) {
+	info, err := f.ledgerInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.ChainID, nil
+}
+
+// BlockTimestamp returns the timestamp of the block holding the given ledger version.
+func (f *AptosFinder) BlockTimestamp(ctx context.Context, version int64) (int64, error) {
+	var block aptosBlock
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("v1/blocks/by_version/%d", version), &block); err != nil {
+		return 0, fmt.Errorf("error getting block of version %d: %v", version, err)
+	}
+
+	return parseMicroseconds(block.BlockTimestamp)
+}
+
+// heightTimestamp returns the timestamp of the block at the given height.
+func (f *AptosFinder) heightTimestamp(ctx context.Context, height int64) (int64, error) {
+	block, err := f.blockByHeight(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseMicroseconds(block.BlockTimestamp)
+}
+
+// blockByHeight returns the block at the given height.
+func (f *AptosFinder) blockByHeight(ctx context.Context, height int64) (*aptosBlock, error) {
+	var block aptosBlock
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("v1/blocks/by_height/%d", height), &block); err != nil {
+		return nil, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	return &block, nil
+}
+
+// ledgerInfo returns the current ledger information of the node.
+func (f *AptosFinder) ledgerInfo(ctx context.Context) (*aptosLedgerInfo, error) {
+	var info aptosLedgerInfo
+	if err := f.client.GetJSON(ctx, "v1", &info); err != nil {
+		return nil, fmt.Errorf("error getting ledger info: %v", err)
+	}
+
+	return &info, nil
+}
+
+// parseMicroseconds parses a timestamp in microseconds into a Unix timestamp.
+func parseMicroseconds(value string) (int64, error) {
+	microseconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing timestamp %q: %v", value, err)
+	}
+
+	return microseconds / 1_000_000, nil
+}
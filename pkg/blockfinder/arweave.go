@@ -0,0 +1,66 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// ArweaveConfirmations is the number of blocks mined on top of an Arweave block before it is considered final.
+const ArweaveConfirmations = 15
+
+// ArweaveFinder finds blocks on Arweave through the HTTP API of its gateways.
+type ArweaveFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder    = (*ArweaveFinder)(nil)
+	_ Chain     = (*ArweaveFinder)(nil)
+	_ Finalizer = (*ArweaveFinder)(nil)
+)
+
+// NewArweaveFinder creates an ArweaveFinder using the given HTTP client.
+func NewArweaveFinder(client HTTPClient) *ArweaveFinder {
+	return &ArweaveFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *ArweaveFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	height, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return searchRange(ctx, 1, height, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the height of the latest block.
+func (f *ArweaveFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var info struct {
+		Height int64 `json:"height"`
+	}
+	if err := f.client.GetJSON(ctx, "info", &info); err != nil {
+		return 0, fmt.Errorf("error getting latest block height: %v", err)
+	}
+
+	return info.Height, nil
+}
+
+// FinalizedHeight implements Finalizer, as Arweave has no finality beyond ArweaveConfirmations.
+func (f *ArweaveFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, ArweaveConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *ArweaveFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var block struct {
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("block/height/%d", height), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	return block.Timestamp, nil
+}
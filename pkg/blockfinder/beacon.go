@@ -0,0 +1,134 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// BeaconFinder finds slots of an Ethereum consensus-layer chain through the standard beacon node API.
+//
+// Slots follow each other at a fixed interval from genesis, so the slot of a timestamp is computed from
+// the chain's genesis time and SECONDS_PER_SLOT rather than searched for. Missed slots are still returned,
+// as beacon workers index by slot. The epoch of a slot is the slot divided by SLOTS_PER_EPOCH.
+type BeaconFinder struct {
+	client HTTPClient
+
+	genesisTime    int64
+	secondsPerSlot int64
+}
+
+var (
+	_ Finder    = (*BeaconFinder)(nil)
+	_ Chain     = (*BeaconFinder)(nil)
+	_ Finalizer = (*BeaconFinder)(nil)
+)
+
+// NewBeaconFinder creates a BeaconFinder using the given HTTP client.
+func NewBeaconFinder(client HTTPClient) *BeaconFinder {
+	return &BeaconFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder. The returned BlockRef holds a slot rather than an execution block number.
+func (f *BeaconFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	if err := f.loadSpec(ctx); err != nil {
+		return BlockRef{}, err
+	}
+
+	headSlot, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// The first slot starting at or after the timestamp, rounding up
+	slot := max((timestamp-f.genesisTime+f.secondsPerSlot-1)/f.secondsPerSlot, 0)
+	slot = min(slot, headSlot)
+
+	return BlockRef{Number: slot, Timestamp: f.slotTime(slot)}, nil
+}
+
+// LatestHeight returns the slot of the head block.
+func (f *BeaconFinder) LatestHeight(ctx context.Context) (int64, error) {
+	return f.headerSlot(ctx, "head")
+}
+
+// FinalizedHeight implements Finalizer with the slot of the finalized block.
+func (f *BeaconFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.headerSlot(ctx, "finalized")
+}
+
+// headerSlot returns the slot of the block identified by blockID, like head or finalized.
+func (f *BeaconFinder) headerSlot(ctx context.Context, blockID string) (int64, error) {
+	var header struct {
+		Data struct {
+			Header struct {
+				Message struct {
+					Slot string `json:"slot"`
+				} `json:"message"`
+			} `json:"header"`
+		} `json:"data"`
+	}
+	if err := f.client.GetJSON(ctx, "eth/v1/beacon/headers/"+blockID, &header); err != nil {
+		return 0, fmt.Errorf("error getting %s slot: %v", blockID, err)
+	}
+
+	slot, err := strconv.ParseInt(header.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s slot: %v", blockID, err)
+	}
+
+	return slot, nil
+}
+
+// BlockTimestamp returns the start time of the given slot.
+func (f *BeaconFinder) BlockTimestamp(ctx context.Context, slot int64) (int64, error) {
+	if err := f.loadSpec(ctx); err != nil {
+		return 0, err
+	}
+
+	return f.slotTime(slot), nil
+}
+
+// slotTime returns the start time of slot.
+func (f *BeaconFinder) slotTime(slot int64) int64 {
+	return f.genesisTime + slot*f.secondsPerSlot
+}
+
+// loadSpec fetches the genesis time and slot duration of the chain, unless already known.
+func (f *BeaconFinder) loadSpec(ctx context.Context) error {
+	if f.secondsPerSlot > 0 {
+		return nil
+	}
+
+	var genesis struct {
+		Data struct {
+			GenesisTime string `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := f.client.GetJSON(ctx, "eth/v1/beacon/genesis", &genesis); err != nil {
+		return fmt.Errorf("error getting genesis: %v", err)
+	}
+
+	genesisTime, err := strconv.ParseInt(genesis.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing genesis time: %v", err)
+	}
+
+	var spec struct {
+		Data struct {
+			SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+		} `json:"data"`
+	}
+	if err := f.client.GetJSON(ctx, "eth/v1/config/spec", &spec); err != nil {
+		return fmt.Errorf("error getting chain spec: %v", err)
+	}
+
+	secondsPerSlot, err := strconv.ParseInt(spec.Data.SecondsPerSlot, 10, 64)
+	if err != nil || secondsPerSlot <= 0 {
+		return fmt.Errorf("error parsing SECONDS_PER_SLOT %q", spec.Data.SecondsPerSlot)
+	}
+
+	f.genesisTime, f.secondsPerSlot = genesisTime, secondsPerSlot
+
+	return nil
+}
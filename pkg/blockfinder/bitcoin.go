@@ -0,0 +1,134 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// BitcoinConfirmations is the number of blocks mined on top of a Bitcoin block before it is considered final.
+const BitcoinConfirmations = 6
+
+// BitcoinFinder finds blocks on Bitcoin (and other bitcoind-derived UTXO chains) through the JSON-RPC of a full node.
+//
+// Bitcoin block timestamps are only loosely ordered, so the returned block is the one the search settles on
+// around the target rather than a strict first block after it.
+type BitcoinFinder struct {
+	cacheable
+
+	client RPCClient
+}
+
+var (
+	_ Finder    = (*BitcoinFinder)(nil)
+	_ Chain     = (*BitcoinFinder)(nil)
+	_ Finalizer = (*BitcoinFinder)(nil)
+)
+
+// NewBitcoinFinder creates a BitcoinFinder using the given RPC client.
+func NewBitcoinFinder(client RPCClient) *BitcoinFinder {
+	return &BitcoinFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *BitcoinFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	height, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return searchRange(ctx, 0, height, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the height of the chain tip.
+func (f *BitcoinFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var height int64
+	if err := f.client.CallContext(ctx, &height, "getblockcount"); err != nil {
+		return 0, fmt.Errorf("error getting block count: %v", err)
+	}
+
+	return height, nil
+}
+
+// FinalizedHeight implements Finalizer, as Bitcoin has no finality beyond BitcoinConfirmations.
+func (f *BitcoinFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, BitcoinConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *BitcoinFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var hash string
+	if err := f.client.CallContext(ctx, &hash, "getblockhash", height); err != nil {
+		return 0, fmt.Errorf("error getting hash of block %d: %v", height, err)
+	}
+
+	var header struct {
+		Time int64 `json:"time"`
+	}
+	if err := f.client.CallContext(ctx, &header, "getblockheader", hash); err != nil {
+		return 0, fmt.Errorf("error getting header of block %d: %v", height, err)
+	}
+
+	return header.Time, nil
+}
+
+// EsploraFinder finds blocks on Bitcoin through an Esplora HTTP API, such as the one hosted by Blockstream,
+// for when no bitcoind credentials are available.
+type EsploraFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder    = (*EsploraFinder)(nil)
+	_ Chain     = (*EsploraFinder)(nil)
+	_ Finalizer = (*EsploraFinder)(nil)
+)
+
+// NewEsploraFinder creates an EsploraFinder using the given HTTP client.
+func NewEsploraFinder(client HTTPClient) *EsploraFinder {
+	return &EsploraFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *EsploraFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	height, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return searchRange(ctx, 0, height, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the height of the chain tip.
+func (f *EsploraFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var height int64
+	if err := f.client.GetJSON(ctx, "blocks/tip/height", &height); err != nil {
+		return 0, fmt.Errorf("error getting tip height: %v", err)
+	}
+
+	return height, nil
+}
+
+// FinalizedHeight implements Finalizer, as Bitcoin has no finality beyond BitcoinConfirmations.
+func (f *EsploraFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, BitcoinConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *EsploraFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	// blocks/:height lists up to ten blocks downwards from height, which saves resolving the block hash first
+	var blocks []struct {
+		Height    int64 `json:"height"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("blocks/%d", height), &blocks); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	if len(blocks) == 0 || blocks[0].Height != height {
+		return 0, fmt.Errorf("error getting block %d: not in response", height)
+	}
+
+	return blocks[0].Timestamp, nil
+}
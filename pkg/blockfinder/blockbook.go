@@ -0,0 +1,107 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// BlockbookFinder reads blocks of UTXO chains such as Dogecoin and Litecoin through the REST API of a Blockbook
+// indexer, for when no node credentials are available. It is searched by a ChainFinder.
+type BlockbookFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Chain     = (*BlockbookFinder)(nil)
+	_ Finalizer = (*BlockbookFinder)(nil)
+)
+
+// NewBlockbookFinder creates a BlockbookFinder using the given HTTP client.
+func NewBlockbookFinder(client HTTPClient) *BlockbookFinder {
+	return &BlockbookFinder{client: client}
+}
+
+// LatestHeight returns the height of the best block indexed by Blockbook.
+func (f *BlockbookFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var status struct {
+		Blockbook struct {
+			BestHeight int64 `json:"bestHeight"`
+		} `json:"blockbook"`
+	}
+	if err := f.client.GetJSON(ctx, "api/v2", &status); err != nil {
+		return 0, fmt.Errorf("error getting status: %v", err)
+	}
+
+	return status.Blockbook.BestHeight, nil
+}
+
+// FinalizedHeight implements Finalizer, waiting for as many confirmations as on Bitcoin.
+func (f *BlockbookFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, BitcoinConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *BlockbookFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var block struct {
+		Time int64 `json:"time"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("api/v2/block/%d", height), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	return block.Time, nil
+}
+
+// InsightFinder reads blocks of UTXO chains through an Insight API, the explorer API older indexers serve.
+// It is searched by a ChainFinder.
+type InsightFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Chain     = (*InsightFinder)(nil)
+	_ Finalizer = (*InsightFinder)(nil)
+)
+
+// NewInsightFinder creates an InsightFinder using the given HTTP client.
+func NewInsightFinder(client HTTPClient) *InsightFinder {
+	return &InsightFinder{client: client}
+}
+
+// LatestHeight returns the height of the best block known to the Insight server.
+func (f *InsightFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var status struct {
+		Info struct {
+			Blocks int64 `json:"blocks"`
+		} `json:"info"`
+	}
+	if err := f.client.GetJSON(ctx, "api/status?q=getInfo", &status); err != nil {
+		return 0, fmt.Errorf("error getting status: %v", err)
+	}
+
+	return status.Info.Blocks, nil
+}
+
+// FinalizedHeight implements Finalizer, waiting for as many confirmations as on Bitcoin.
+func (f *InsightFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, BitcoinConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *InsightFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var index struct {
+		BlockHash string `json:"blockHash"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("api/block-index/%d", height), &index); err != nil {
+		return 0, fmt.Errorf("error getting hash of block %d: %v", height, err)
+	}
+
+	var block struct {
+		Time int64 `json:"time"`
+	}
+	if err := f.client.GetJSON(ctx, "api/block/"+index.BlockHash, &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	return block.Time, nil
+}
@@ -0,0 +1,249 @@
+// Package blockfinder resolves the block of a chain that was produced at a given point in time.
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrBlockNotFound is returned by a TimestampFunc for heights without a block, such as skipped Solana slots.
+var ErrBlockNotFound = errors.New("block not found")
+
+// ErrMalformedResponse is wrapped by the errors of answers that don't make sense, such as blocks without timestamps
+// or numbers that aren't hex, as opposed to blocks the endpoint doesn't have.
+var ErrMalformedResponse = errors.New("malformed response")
+
+// ErrInvalidTimestamp is wrapped by the errors of searches running into blocks whose timestamps can't be genuine,
+// like zero or far-future ones, which would otherwise send the search to the wrong block.
+var ErrInvalidTimestamp = errors.New("invalid block timestamp")
+
+// MaxTimestamp is the latest block timestamp taken as genuine, the last second of the year 9999.
+// Later ones come from broken chains or endpoints, and can't be formatted as times anyway.
+const MaxTimestamp int64 = 253402300799
+
+// BlockRef identifies a block by its number (or height) and timestamp.
+type BlockRef struct {
+	Number    int64 `json:"number"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Finder finds the block of a chain closest to a Unix timestamp.
+type Finder interface {
+	// FindBlockByTimestamp returns the first block with a timestamp at or after the given one.
+	// Timestamps before the first block return the first block, and timestamps after the head return the head.
+	FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error)
+}
+
+// TimestampFunc returns the timestamp of the block at the given height.
+type TimestampFunc func(ctx context.Context, height int64) (int64, error)
+
+// BatchTimestampFunc returns the timestamps of the blocks at the given heights, fetched together.
+type BatchTimestampFunc func(ctx context.Context, heights []int64) ([]int64, error)
+
+// refineWindow is the size of the range below which a batched search fetches every remaining block at once.
+const refineWindow = 16
+
+// Search finds the first block at or after timestamp between low and high, whose timestamps are already known.
+// Blocks sharing a timestamp don't stop the search, so the earliest of them is returned whatever path the search
+// took to them, and the answer doesn't depend on the head of the node queried. Targets at or before low return low,
+// and targets after high return high, the only block returned that is before the target.
+//
+// Each step interpolates the target's position from the timestamps at both ends of the range, assuming a steady
+// block time, which lands close to the target in a handful of calls. If a guess fails to at least halve the range
+// (e.g. the block time changed over the chain's history), the next step bisects instead, so the search never
+// takes more than about twice the calls of a plain binary search.
+func Search(ctx context.Context, low, high BlockRef, timestamp int64, timestampAt TimestampFunc) (BlockRef, error) {
+	return BatchSearch(ctx, low, high, timestamp, timestampAt, nil)
+}
+
+// BatchSearch is Search for chains that can fetch several blocks in one request: once the range is down to
+// a few blocks, all of them are fetched with timestampsAt in a single request and the first one at or after
+// the target is picked locally, saving the round trips of the last search steps. A nil timestampsAt makes it the same as Search.
+func BatchSearch(ctx context.Context, low, high BlockRef, timestamp int64, timestampAt TimestampFunc, timestampsAt BatchTimestampFunc) (BlockRef, error) {
+	if err := checkRange(low, high); err != nil {
+		return BlockRef{}, err
+	}
+
+	timestampAt, timestampsAt = checkedTimestamps(timestampAt, timestampsAt)
+
+	if timestamp <= low.Timestamp {
+		return low, nil
+	}
+	if timestamp > high.Timestamp {
+		return high, nil
+	}
+
+	var speculation *prefetcher
+	if speculating(ctx) {
+		speculation = newPrefetcher(timestampAt)
+		timestampAt = speculation.TimestampAt
+	}
+
+	// speculated is set once the midpoints of both halves of the range were fetched, to bisect again with one of them
+	bisect, speculated := false, false
+
+	// Invariant: low.Timestamp < timestamp <= high.Timestamp, so high is always a candidate and low never is
+	for high.Number-low.Number > 1 {
+		reportProgress(ctx, low.Number, high.Number)
+
+		if timestampsAt != nil && high.Number-low.Number <= refineWindow {
+			return refine(ctx, low, high, timestamp, timestampsAt)
+		}
+
+		var number int64
+		if bisect || speculated {
+			number = low.Number + (high.Number-low.Number)/2
+		} else {
+			number = low.Number + interpolate(timestamp-low.Timestamp, high.Number-low.Number, high.Timestamp-low.Timestamp)
+		}
+
+		// Keep the guess strictly inside the range so every step makes progress
+		number = max(low.Number+1, min(number, high.Number-1))
+
+		if speculation != nil && bisect && !speculated {
+			speculation.prefetch(ctx, number, low.Number+(number-low.Number)/2, number+(high.Number-number)/2)
+		}
+
+		// Chains that skip heights may have no block at the guess, so settle for the nearest one inside the range
+		mid, found, err := nearestBlock(ctx, number, high.Number, timestampAt)
+		if err == nil && !found {
+			mid, found, err = nearestBlock(ctx, number-1, low.Number, timestampAt)
+		}
+		if err != nil {
+			return BlockRef{}, err
+		}
+		if !found {
+			// There are no blocks between low and high at all
+			return high, nil
+		}
+
+		previousRange := high.Number - low.Number
+
+		// A block at exactly the target may follow others with the same timestamp, so it only bounds the range
+		if mid.Timestamp < timestamp {
+			low = mid
+		} else {
+			high = mid
+		}
+
+		if speculated {
+			// The second level of a speculative bisection, after which the search goes on as usual
+			bisect, speculated = false, false
+		} else {
+			speculated = speculation != nil && bisect
+			bisect = !bisect && high.Number-low.Number > previousRange/2
+		}
+	}
+
+	return high, nil
+}
+
+// refine fetches every block strictly between low and high at once and returns the first one at or after timestamp,
+// or high if there is none.
+func refine(ctx context.Context, low, high BlockRef, timestamp int64, timestampsAt BatchTimestampFunc) (BlockRef, error) {
+	heights := make([]int64, 0, high.Number-low.Number-1)
+	for height := low.Number + 1; height < high.Number; height++ {
+		heights = append(heights, height)
+	}
+
+	timestamps, err := timestampsAt(ctx, heights)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	for index, height := range heights {
+		if timestamps[index] >= timestamp {
+			return BlockRef{Number: height, Timestamp: timestamps[index]}, nil
+		}
+	}
+
+	return high, nil
+}
+
+// nearestBlock walks from height towards limit (exclusive) and returns the first block that exists.
+func nearestBlock(ctx context.Context, height, limit int64, timestampAt TimestampFunc) (BlockRef, bool, error) {
+	step := int64(1)
+	if limit < height {
+		step = -1
+	}
+
+	for ; height != limit; height += step {
+		timestamp, err := timestampAt(ctx, height)
+		if errors.Is(err, ErrBlockNotFound) {
+			continue
+		}
+		if err != nil {
+			return BlockRef{}, false, err
+		}
+
+		return BlockRef{Number: height, Timestamp: timestamp}, true, nil
+	}
+
+	return BlockRef{}, false, nil
+}
+
+// checkTimestamp returns an error wrapping ErrInvalidTimestamp if block can't have been produced at its timestamp.
+// The first block searched is chosen to have a usable timestamp, so zero timestamps are never genuine either.
+func checkTimestamp(block BlockRef) error {
+	if block.Timestamp <= 0 || block.Timestamp > MaxTimestamp {
+		return fmt.Errorf("%w: block %d has timestamp %d", ErrInvalidTimestamp, block.Number, block.Timestamp)
+	}
+
+	return nil
+}
+
+// checkRange checks the timestamps of the ends of a search, which must be in order.
+func checkRange(low, high BlockRef) error {
+	for _, block := range []BlockRef{low, high} {
+		if err := checkTimestamp(block); err != nil {
+			return err
+		}
+	}
+
+	if low.Number <= high.Number && low.Timestamp > high.Timestamp {
+		return fmt.Errorf("%w: block %d has timestamp %d, later than %d of block %d",
+			ErrInvalidTimestamp, low.Number, low.Timestamp, high.Timestamp, high.Number)
+	}
+
+	return nil
+}
+
+// checkedTimestamps wraps timestampAt and timestampsAt, if set, so that they fail on invalid timestamps.
+func checkedTimestamps(timestampAt TimestampFunc, timestampsAt BatchTimestampFunc) (TimestampFunc, BatchTimestampFunc) {
+	checkedAt := func(ctx context.Context, height int64) (int64, error) {
+		timestamp, err := timestampAt(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+
+		return timestamp, checkTimestamp(BlockRef{Number: height, Timestamp: timestamp})
+	}
+
+	if timestampsAt == nil {
+		return checkedAt, nil
+	}
+
+	return checkedAt, func(ctx context.Context, heights []int64) ([]int64, error) {
+		timestamps, err := timestampsAt(ctx, heights)
+		if err != nil {
+			return nil, err
+		}
+
+		for index, height := range heights {
+			if err := checkTimestamp(BlockRef{Number: height, Timestamp: timestamps[index]}); err != nil {
+				return nil, err
+			}
+		}
+
+		return timestamps, nil
+	}
+}
+
+// interpolate returns offset * span / total, using big integers so large heights can't overflow.
+func interpolate(offset, span, total int64) int64 {
+	result := new(big.Int).Mul(big.NewInt(offset), big.NewInt(span))
+
+	return result.Div(result, big.NewInt(total)).Int64()
+}
@@ -0,0 +1,230 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"get-node-start-block/pkg/mockchain"
+)
+
+// fixtureTimestamps returns the timestamp functions of chain, counting the blocks fetched in batches in batched.
+func fixtureTimestamps(chain mockchain.Chain, batched *int) (TimestampFunc, BatchTimestampFunc) {
+	timestampAt := func(_ context.Context, height int64) (int64, error) {
+		if height < 0 || height > chain.Height {
+			return 0, ErrBlockNotFound
+		}
+
+		return chain.Timestamp(height), nil
+	}
+
+	timestampsAt := func(ctx context.Context, heights []int64) ([]int64, error) {
+		*batched++
+
+		timestamps := make([]int64, 0, len(heights))
+		for _, height := range heights {
+			timestamp, err := timestampAt(ctx, height)
+			if err != nil {
+				return nil, err
+			}
+
+			timestamps = append(timestamps, timestamp)
+		}
+
+		return timestamps, nil
+	}
+
+	return timestampAt, timestampsAt
+}
+
+// firstBlockAtOrAfter returns the expected answer of a search of chain for timestamp, found by scanning every block.
+func firstBlockAtOrAfter(chain mockchain.Chain, timestamp int64) BlockRef {
+	for number := int64(0); number < chain.Height; number++ {
+		if chain.Timestamp(number) >= timestamp {
+			return BlockRef{Number: number, Timestamp: chain.Timestamp(number)}
+		}
+	}
+
+	return BlockRef{Number: chain.Height, Timestamp: chain.Timestamp(chain.Height)}
+}
+
+func TestBatchSearch(t *testing.T) {
+	steady := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 12, Height: 100_000}
+	// Four blocks share every timestamp, like on chains producing several blocks a second
+	shared := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 0.25, Height: 100_000}
+	// Blocks are one or two seconds apart, so interpolated guesses are off by a few blocks
+	uneven := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 1.3, Height: 100_000}
+
+	tests := []struct {
+		name      string
+		chain     mockchain.Chain
+		timestamp int64
+		batch     bool
+		// want is the expected block, found by scanning the chain if unset
+		want *BlockRef
+	}{
+		{name: "steady chain", chain: steady, timestamp: 1_600_123_456},
+		{name: "steady chain in batches", chain: steady, timestamp: 1_600_123_456, batch: true},
+		{name: "exact timestamp", chain: steady, timestamp: steady.Timestamp(4242)},
+		{name: "run of equal timestamps", chain: shared, timestamp: shared.Timestamp(40_001)},
+		{name: "run of equal timestamps in batches", chain: shared, timestamp: shared.Timestamp(40_001), batch: true},
+		{name: "uneven block time", chain: uneven, timestamp: 1_600_100_000},
+		{name: "uneven block time in batches", chain: uneven, timestamp: 1_600_100_000, batch: true},
+		{
+			name: "target before first block", chain: steady, timestamp: 1_500_000_000,
+			want: &BlockRef{Number: 0, Timestamp: steady.GenesisTime},
+		},
+		{
+			name: "target after head", chain: steady, timestamp: 2_000_000_000, batch: true,
+			want: &BlockRef{Number: steady.Height, Timestamp: steady.Timestamp(steady.Height)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var batched int
+
+			timestampAt, timestampsAt := fixtureTimestamps(test.chain, &batched)
+			if !test.batch {
+				timestampsAt = nil
+			}
+
+			low := BlockRef{Number: 0, Timestamp: test.chain.Timestamp(0)}
+			high := BlockRef{Number: test.chain.Height, Timestamp: test.chain.Timestamp(test.chain.Height)}
+
+			got, err := BatchSearch(context.Background(), low, high, test.timestamp, timestampAt, timestampsAt)
+			if err != nil {
+				t.Fatalf("BatchSearch() error = %v", err)
+			}
+
+			want := firstBlockAtOrAfter(test.chain, test.timestamp)
+			if test.want != nil {
+				want = *test.want
+			}
+
+			if got != want {
+				t.Errorf("BatchSearch() = %+v, want %+v", got, want)
+			}
+
+			if !test.batch && batched > 0 {
+				t.Errorf("BatchSearch() fetched %d batches without a batch function", batched)
+			}
+		})
+	}
+}
+
+func TestBatchSearchRefine(t *testing.T) {
+	chain := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 0.5, Height: refineWindow}
+
+	// Every block strictly between the ends of the range is fetched in a single batch, whichever of the blocks sharing
+	// its timestamp the target is
+	for timestamp := chain.GenesisTime + 1; timestamp <= chain.Timestamp(chain.Height); timestamp++ {
+		var batched, single int
+
+		timestampAt, timestampsAt := fixtureTimestamps(chain, &batched)
+		countedAt := func(ctx context.Context, height int64) (int64, error) {
+			single++
+			return timestampAt(ctx, height)
+		}
+
+		low := BlockRef{Number: 0, Timestamp: chain.Timestamp(0)}
+		high := BlockRef{Number: chain.Height, Timestamp: chain.Timestamp(chain.Height)}
+
+		got, err := BatchSearch(context.Background(), low, high, timestamp, countedAt, timestampsAt)
+		if err != nil {
+			t.Fatalf("BatchSearch(%d) error = %v", timestamp, err)
+		}
+
+		if want := firstBlockAtOrAfter(chain, timestamp); got != want {
+			t.Errorf("BatchSearch(%d) = %+v, want %+v", timestamp, got, want)
+		}
+
+		if batched != 1 || single != 0 {
+			t.Errorf("BatchSearch(%d) fetched %d batches and %d single blocks, want a single batch", timestamp, batched, single)
+		}
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high BlockRef
+		valid     bool
+	}{
+		{name: "in order", low: BlockRef{Number: 1, Timestamp: 100}, high: BlockRef{Number: 10, Timestamp: 200}, valid: true},
+		{name: "equal timestamps", low: BlockRef{Number: 1, Timestamp: 100}, high: BlockRef{Number: 10, Timestamp: 100}, valid: true},
+		{name: "latest genuine timestamp", low: BlockRef{Number: 1, Timestamp: 100}, high: BlockRef{Number: 10, Timestamp: MaxTimestamp}, valid: true},
+		{name: "zero timestamp", low: BlockRef{Number: 0, Timestamp: 0}, high: BlockRef{Number: 10, Timestamp: 200}},
+		{name: "negative timestamp", low: BlockRef{Number: 1, Timestamp: -1}, high: BlockRef{Number: 10, Timestamp: 200}},
+		{name: "huge timestamp", low: BlockRef{Number: 1, Timestamp: 100}, high: BlockRef{Number: 10, Timestamp: MaxTimestamp + 1}},
+		{name: "out of order", low: BlockRef{Number: 1, Timestamp: 300}, high: BlockRef{Number: 10, Timestamp: 200}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkRange(test.low, test.high)
+
+			if test.valid && err != nil {
+				t.Errorf("checkRange() error = %v, want nil", err)
+			}
+
+			if !test.valid && !errors.Is(err, ErrInvalidTimestamp) {
+				t.Errorf("checkRange() error = %v, want ErrInvalidTimestamp", err)
+			}
+		})
+	}
+}
+
+func TestSearchInvalidTimestamps(t *testing.T) {
+	chain := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 12, Height: 10_000}
+
+	tests := []struct {
+		name string
+		// corrupt is the timestamp the endpoint answers for block 5000 instead of the genuine one
+		corrupt int64
+	}{
+		{name: "zero timestamp", corrupt: 0},
+		{name: "huge timestamp", corrupt: MaxTimestamp + 1},
+	}
+
+	for _, test := range tests {
+		for _, batch := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s batch=%t", test.name, batch), func(t *testing.T) {
+				var batched int
+
+				timestampAt, timestampsAt := fixtureTimestamps(chain, &batched)
+				corruptAt := func(ctx context.Context, height int64) (int64, error) {
+					if height == 5000 {
+						return test.corrupt, nil
+					}
+
+					return timestampAt(ctx, height)
+				}
+
+				corruptsAt := func(ctx context.Context, heights []int64) ([]int64, error) {
+					timestamps, err := timestampsAt(ctx, heights)
+					for index, height := range heights {
+						if height == 5000 && err == nil {
+							timestamps[index] = test.corrupt
+						}
+					}
+
+					return timestamps, err
+				}
+				if !batch {
+					corruptsAt = nil
+				}
+
+				low := BlockRef{Number: 0, Timestamp: chain.Timestamp(0)}
+				high := BlockRef{Number: chain.Height, Timestamp: chain.Timestamp(chain.Height)}
+
+				// The target is the corrupt block, so every search runs into it
+				_, err := BatchSearch(context.Background(), low, high, chain.Timestamp(5000), corruptAt, corruptsAt)
+				if !errors.Is(err, ErrInvalidTimestamp) {
+					t.Errorf("BatchSearch() error = %v, want ErrInvalidTimestamp", err)
+				}
+			})
+		}
+	}
+}
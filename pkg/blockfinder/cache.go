@@ -0,0 +1,85 @@
+package blockfinder
+
+import "context"
+
+// TimestampCache keeps the block timestamps of a single network, usually across runs.
+type TimestampCache interface {
+	Timestamp(number int64) (int64, bool)
+	SetTimestamp(number, timestamp int64)
+}
+
+// Cacheable is implemented by finders that can look block timestamps up in a TimestampCache during their searches.
+type Cacheable interface {
+	SetCache(cache TimestampCache)
+}
+
+// cacheable is embedded by finders to implement Cacheable.
+type cacheable struct {
+	cache TimestampCache
+}
+
+// SetCache makes the finder look block timestamps up in cache before asking the chain, and store the ones it fetched.
+func (c *cacheable) SetCache(cache TimestampCache) {
+	c.cache = cache
+}
+
+// cached wraps timestampAt with the cache, if there is one.
+func (c *cacheable) cached(timestampAt TimestampFunc) TimestampFunc {
+	if c.cache == nil {
+		return timestampAt
+	}
+
+	return func(ctx context.Context, number int64) (int64, error) {
+		if timestamp, ok := c.cache.Timestamp(number); ok {
+			return timestamp, nil
+		}
+
+		timestamp, err := timestampAt(ctx, number)
+		if err == nil {
+			c.cache.SetTimestamp(number, timestamp)
+		}
+
+		return timestamp, err
+	}
+}
+
+// cachedBatch wraps timestampsAt with the cache, if there is one, only fetching the heights missing from it.
+func (c *cacheable) cachedBatch(timestampsAt BatchTimestampFunc) BatchTimestampFunc {
+	if c.cache == nil {
+		return timestampsAt
+	}
+
+	return func(ctx context.Context, heights []int64) ([]int64, error) {
+		timestamps := make([]int64, len(heights))
+
+		var missingHeights []int64
+		var missingIndexes []int
+
+		for index, height := range heights {
+			timestamp, ok := c.cache.Timestamp(height)
+			if !ok {
+				missingHeights = append(missingHeights, height)
+				missingIndexes = append(missingIndexes, index)
+				continue
+			}
+
+			timestamps[index] = timestamp
+		}
+
+		if len(missingHeights) == 0 {
+			return timestamps, nil
+		}
+
+		fetched, err := timestampsAt(ctx, missingHeights)
+		if err != nil {
+			return nil, err
+		}
+
+		for position, index := range missingIndexes {
+			timestamps[index] = fetched[position]
+			c.cache.SetTimestamp(heights[index], fetched[position])
+		}
+
+		return timestamps, nil
+	}
+}
@@ -0,0 +1,249 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoChainID is returned by ChainFinder.ChainID for chains without a chain ID.
+	ErrNoChainID = errors.New("chain has no chain ID")
+	// ErrNoFinality is returned by ChainFinder.FinalizedHeight for chains that can't tell which blocks are final.
+	ErrNoFinality = errors.New("chain has no finality information")
+)
+
+// Chain is the least a chain family implements for its blocks to be found by timestamp:
+// the height of its head and the timestamp of the block at any height.
+type Chain interface {
+	LatestHeight(ctx context.Context) (int64, error)
+	BlockTimestamp(ctx context.Context, height int64) (int64, error)
+}
+
+// BatchChain is a Chain that can also look up the timestamps of several blocks in a single request.
+type BatchChain interface {
+	Chain
+	BlockTimestamps(ctx context.Context, heights []int64) ([]int64, error)
+}
+
+// ChainIdentifier is implemented by chains identifying themselves with a chain ID, like EVM chains with eth_chainId.
+type ChainIdentifier interface {
+	ChainID(ctx context.Context) (int64, error)
+}
+
+// ArchiveChecker is implemented by finders that can check their endpoints serve the whole history of the chain,
+// which searching needs.
+type ArchiveChecker interface {
+	// CheckArchive returns an error if old blocks are unavailable, as on pruned nodes.
+	CheckArchive(ctx context.Context) error
+}
+
+// Finalizer is implemented by chains that can tell up to which height their blocks can no longer be reorganized,
+// either from the finality of their consensus or from a number of confirmations.
+type Finalizer interface {
+	FinalizedHeight(ctx context.Context) (int64, error)
+}
+
+// ChainFinder finds blocks of any Chain by searching its blocks from a first height up to its head.
+type ChainFinder struct {
+	cacheable
+
+	chain       Chain
+	firstHeight int64
+	scanWindow  int64
+}
+
+var (
+	_ Finder          = (*ChainFinder)(nil)
+	_ Chain           = (*ChainFinder)(nil)
+	_ ChainIdentifier = (*ChainFinder)(nil)
+	_ ArchiveChecker  = (*ChainFinder)(nil)
+	_ Finalizer       = (*ChainFinder)(nil)
+)
+
+// NewChainFinder creates a ChainFinder searching chain from firstHeight on, the first block with a usable timestamp.
+func NewChainFinder(chain Chain, firstHeight int64) *ChainFinder {
+	return &ChainFinder{chain: chain, firstHeight: firstHeight}
+}
+
+// SetScanWindow makes the finder check the blocks up to window blocks on both sides of the block a search found,
+// for chains whose timestamps aren't monotonic within small windows, like Arbitrum, whose sequencer may stamp a block
+// ahead of the ones following it. 0 trusts the search.
+func (f *ChainFinder) SetScanWindow(window int64) {
+	f.scanWindow = window
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *ChainFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	height, err := f.chain.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error getting latest height: %v", err)
+	}
+
+	timestampAt := f.cached(f.chain.BlockTimestamp)
+
+	var timestampsAt BatchTimestampFunc
+	if batchChain, ok := f.chain.(BatchChain); ok {
+		timestampsAt = f.cachedBatch(batchChain.BlockTimestamps)
+	}
+
+	low, high, err := rangeEnds(ctx, f.firstHeight, height, timestampAt)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	found, err := BatchSearch(ctx, low, high, timestamp, timestampAt, timestampsAt)
+	if err != nil || f.scanWindow <= 0 {
+		return found, err
+	}
+
+	return scanWindow(ctx, low, high, found, timestamp, f.scanWindow, timestampAt, timestampsAt)
+}
+
+// LatestHeight implements Chain.
+func (f *ChainFinder) LatestHeight(ctx context.Context) (int64, error) {
+	return f.chain.LatestHeight(ctx)
+}
+
+// BlockTimestamp implements Chain.
+func (f *ChainFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	return f.chain.BlockTimestamp(ctx, height)
+}
+
+// ChainID implements ChainIdentifier, returning ErrNoChainID if the chain has no chain ID.
+func (f *ChainFinder) ChainID(ctx context.Context) (int64, error) {
+	identifier, ok := f.chain.(ChainIdentifier)
+	if !ok {
+		return 0, ErrNoChainID
+	}
+
+	return identifier.ChainID(ctx)
+}
+
+// FinalizedHeight implements Finalizer, returning ErrNoFinality if the chain can't tell which blocks are final.
+func (f *ChainFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	finalizer, ok := f.chain.(Finalizer)
+	if !ok {
+		return 0, ErrNoFinality
+	}
+
+	return finalizer.FinalizedHeight(ctx)
+}
+
+// CheckArchive implements ArchiveChecker by fetching the first block searched and one halfway through the history.
+// These bypass the cache, as it may hold blocks the endpoints no longer serve.
+func (f *ChainFinder) CheckArchive(ctx context.Context) error {
+	height, err := f.chain.LatestHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting latest height: %v", err)
+	}
+
+	for _, probed := range []int64{f.firstHeight, f.firstHeight + (height-f.firstHeight)/2} {
+		if _, err := f.chain.BlockTimestamp(ctx, probed); err != nil {
+			return fmt.Errorf("endpoint is not archival, block %d is unavailable: %v", probed, err)
+		}
+	}
+
+	return nil
+}
+
+// confirmedHeight returns the height of the block with the given number of confirmations on chain,
+// for chains with probabilistic finality.
+func confirmedHeight(ctx context.Context, chain Chain, confirmations int64) (int64, error) {
+	height, err := chain.LatestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(height-confirmations, 0), nil
+}
+
+// searchRange searches the blocks from height low up to height high, looking up the timestamps of both ends first.
+func searchRange(ctx context.Context, low, high, timestamp int64, timestampAt TimestampFunc) (BlockRef, error) {
+	lowRef, highRef, err := rangeEnds(ctx, low, high, timestampAt)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return Search(ctx, lowRef, highRef, timestamp, timestampAt)
+}
+
+// rangeEnds looks up the timestamps of the blocks at heights low and high. Early blocks of some chains, like the
+// genesis blocks of POA chains such as Gnosis, are missing or stamped with zero or bogus timestamps, which would
+// send the search to the wrong block, so the low end is moved up to the first block with a sane timestamp.
+func rangeEnds(ctx context.Context, low, high int64, timestampAt TimestampFunc) (BlockRef, BlockRef, error) {
+	highRef := BlockRef{Number: high}
+
+	var err error
+	if highRef.Timestamp, err = timestampAt(ctx, highRef.Number); err != nil {
+		return BlockRef{}, BlockRef{}, err
+	}
+
+	lowRef, err := firstSaneBlock(ctx, low, highRef, timestampAt)
+	if err != nil {
+		return BlockRef{}, BlockRef{}, err
+	}
+
+	return lowRef, highRef, nil
+}
+
+// firstSaneBlock returns the first block from height low with a sane timestamp, one that is genuine and not after
+// the one of high. Insane blocks are taken to only come before sane ones, so their end is found by galloping
+// forward from low and bisecting the last step, in a few calls even for long runs of them.
+func firstSaneBlock(ctx context.Context, low int64, high BlockRef, timestampAt TimestampFunc) (BlockRef, error) {
+	sane := func(height int64) (BlockRef, bool, error) {
+		timestamp, err := timestampAt(ctx, height)
+		if errors.Is(err, ErrBlockNotFound) {
+			return BlockRef{}, false, nil
+		}
+		if err != nil {
+			return BlockRef{}, false, err
+		}
+
+		block := BlockRef{Number: height, Timestamp: timestamp}
+
+		return block, checkTimestamp(block) == nil && timestamp <= high.Timestamp, nil
+	}
+
+	block, ok, err := sane(low)
+	if err != nil || ok {
+		return block, err
+	}
+
+	// Invariant: the block at insane isn't sane, and the one at found is
+	insane, found := low, BlockRef{}
+	for step := int64(1); ; step *= 2 {
+		height := min(insane+step, high.Number)
+
+		if block, ok, err = sane(height); err != nil {
+			return BlockRef{}, err
+		}
+
+		if ok {
+			found = block
+			break
+		}
+
+		if height == high.Number {
+			return BlockRef{}, fmt.Errorf("%w: no block from %d to %d has a usable timestamp", ErrInvalidTimestamp, low, high.Number)
+		}
+
+		insane = height
+	}
+
+	for found.Number-insane > 1 {
+		middle := insane + (found.Number-insane)/2
+
+		if block, ok, err = sane(middle); err != nil {
+			return BlockRef{}, err
+		}
+
+		if ok {
+			found = block
+		} else {
+			insane = middle
+		}
+	}
+
+	return found, nil
+}
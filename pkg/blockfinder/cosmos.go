@@ -0,0 +1,148 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HTTPClient is the subset of endpoint.Pool used to query chains exposing a plain HTTP API.
+type HTTPClient interface {
+	GetJSON(ctx context.Context, path string, result interface{}) error
+	PostJSON(ctx context.Context, path string, body, result interface{}) error
+}
+
+// CosmosFinder finds blocks on Tendermint (CometBFT) chains such as Cosmos Hub, Osmosis and Celestia,
+// through the /status and /block endpoints of their RPC.
+type CosmosFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder    = (*CosmosFinder)(nil)
+	_ Chain     = (*CosmosFinder)(nil)
+	_ Finalizer = (*CosmosFinder)(nil)
+)
+
+// NewCosmosFinder creates a CosmosFinder using the given HTTP client.
+func NewCosmosFinder(client HTTPClient) *CosmosFinder {
+	return &CosmosFinder{client: client}
+}
+
+// tendermintResponse is the JSON-RPC envelope of Tendermint RPC responses.
+type tendermintResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *CosmosFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	status, err := f.status(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	high, err := parseHeight(status.SyncInfo.LatestBlockHeight)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing latest block height: %v", err)
+	}
+
+	// Pruned nodes only serve blocks from their earliest height on
+	low, err := parseHeight(status.SyncInfo.EarliestBlockHeight)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing earliest block height: %v", err)
+	}
+
+	return Search(ctx,
+		BlockRef{Number: low, Timestamp: status.SyncInfo.EarliestBlockTime.Unix()},
+		BlockRef{Number: high, Timestamp: status.SyncInfo.LatestBlockTime.Unix()},
+		timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the height of the latest block.
+func (f *CosmosFinder) LatestHeight(ctx context.Context) (int64, error) {
+	status, err := f.status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	height, err := parseHeight(status.SyncInfo.LatestBlockHeight)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing latest block height: %v", err)
+	}
+
+	return height, nil
+}
+
+// FinalizedHeight implements Finalizer. Tendermint blocks are final as soon as they are committed,
+// so this is the latest height.
+func (f *CosmosFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.LatestHeight(ctx)
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *CosmosFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var block struct {
+		Block struct {
+			Header struct {
+				Time time.Time `json:"time"`
+			} `json:"header"`
+		} `json:"block"`
+	}
+	if err := f.get(ctx, fmt.Sprintf("block?height=%d", height), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	return block.Block.Header.Time.Unix(), nil
+}
+
+// tendermintStatus is the result of the Tendermint /status endpoint.
+type tendermintStatus struct {
+	SyncInfo struct {
+		LatestBlockHeight   string    `json:"latest_block_height"`
+		LatestBlockTime     time.Time `json:"latest_block_time"`
+		EarliestBlockHeight string    `json:"earliest_block_height"`
+		EarliestBlockTime   time.Time `json:"earliest_block_time"`
+	} `json:"sync_info"`
+}
+
+// status returns the sync status of the node.
+func (f *CosmosFinder) status(ctx context.Context) (*tendermintStatus, error) {
+	var status tendermintStatus
+	if err := f.get(ctx, "status", &status); err != nil {
+		return nil, fmt.Errorf("error getting status: %v", err)
+	}
+
+	return &status, nil
+}
+
+// get queries the Tendermint RPC at path and decodes the result into result.
+func (f *CosmosFinder) get(ctx context.Context, path string, result interface{}) error {
+	var response tendermintResponse
+	if err := f.client.GetJSON(ctx, path, &response); err != nil {
+		return err
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("rpc error %d: %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	return nil
+}
+
+// parseHeight parses a block height, which Tendermint encodes as a decimal string.
+func parseHeight(value string) (int64, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
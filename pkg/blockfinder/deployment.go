@@ -0,0 +1,86 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNoContracts is returned by ChainFinder.DeploymentBlock and ChainFinder.FirstLogBlock for chains without contracts.
+var ErrNoContracts = errors.New("chain has no contracts")
+
+// DeploymentFinder is implemented by finders of chains with contracts, which can find the block a contract
+// was deployed in.
+type DeploymentFinder interface {
+	DeploymentBlock(ctx context.Context, address string) (BlockRef, error)
+}
+
+var (
+	_ DeploymentFinder = (*EthereumFinder)(nil)
+	_ DeploymentFinder = (*ChainFinder)(nil)
+)
+
+// DeploymentBlock implements DeploymentFinder by bisecting the blocks up to the latest one for the first
+// that has code at address. This reads the state of old blocks, which only archive nodes keep.
+// Contracts destroyed and deployed again at the same address aren't supported.
+func (f *EthereumFinder) DeploymentBlock(ctx context.Context, address string) (BlockRef, error) {
+	latest, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	deployed, err := f.hasCode(ctx, address, latest)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	if !deployed {
+		return BlockRef{}, fmt.Errorf("no contract at %s as of block %d", address, latest)
+	}
+
+	// Invariant: there is code at high, and none at low, the genesis block holding no contracts but precompiles
+	low, high := int64(0), latest
+	for high-low > 1 {
+		middle := low + (high-low)/2
+
+		if deployed, err = f.hasCode(ctx, address, middle); err != nil {
+			return BlockRef{}, err
+		}
+
+		if deployed {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+
+	timestamp, err := f.BlockTimestamp(ctx, high)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return BlockRef{Number: high, Timestamp: timestamp}, nil
+}
+
+// hasCode returns whether there was code at address as of the given block.
+func (f *EthereumFinder) hasCode(ctx context.Context, address string, number int64) (bool, error) {
+	var code hexutil.Bytes
+	if err := f.client.CallContext(ctx, &code, "eth_getCode", address, hexutil.EncodeBig(big.NewInt(number))); err != nil {
+		return false, fmt.Errorf("error getting code of %s at block %d: %v", address, number, err)
+	}
+
+	return len(code) > 0, nil
+}
+
+// DeploymentBlock implements DeploymentFinder, returning ErrNoContracts if the chain has no contracts.
+func (f *ChainFinder) DeploymentBlock(ctx context.Context, address string) (BlockRef, error) {
+	finder, ok := f.chain.(DeploymentFinder)
+	if !ok {
+		return BlockRef{}, ErrNoContracts
+	}
+
+	return finder.DeploymentBlock(ctx, address)
+}
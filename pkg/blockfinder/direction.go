@@ -0,0 +1,86 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Direction selects which block around a timestamp to pick.
+type Direction string
+
+const (
+	// After picks the first block at or after the timestamp, so that nothing from the timestamp on is missed.
+	After Direction = "after"
+	// Before picks the last block at or before the timestamp, the last of the blocks sharing it if there are several,
+	// as on chains producing several blocks a second.
+	Before Direction = "before"
+	// Closest picks whichever of the two is closer to the timestamp, preferring After on a tie.
+	Closest Direction = "closest"
+)
+
+// ParseDirection parses a direction name.
+func ParseDirection(value string) (Direction, error) {
+	switch direction := Direction(value); direction {
+	case After, Before, Closest:
+		return direction, nil
+	default:
+		return "", fmt.Errorf("invalid direction %q: must be after, before or closest", value)
+	}
+}
+
+// Pick returns the block in direction from timestamp, given the block found returned by Finder.FindBlockByTimestamp.
+// That block may only lie before the timestamp if it is the chain head, and is the first of the blocks sharing its
+// timestamp otherwise.
+func Pick(ctx context.Context, found BlockRef, timestamp int64, direction Direction, timestampAt TimestampFunc) (BlockRef, error) {
+	if direction == Before && found.Timestamp == timestamp {
+		return lastOfRun(ctx, found, timestampAt)
+	}
+
+	if direction == After || found.Timestamp <= timestamp {
+		return found, nil
+	}
+
+	previous, ok, err := nearestBlock(ctx, found.Number-1, -1, timestampAt)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error getting block before %d: %v", found.Number, err)
+	}
+
+	// Nothing precedes the earliest available block
+	if !ok || previous.Timestamp > timestamp {
+		if direction == Before {
+			return BlockRef{}, fmt.Errorf("no block at or before timestamp %d, the earliest is block %d at %d", timestamp, found.Number, found.Timestamp)
+		}
+
+		return found, nil
+	}
+
+	if direction == Closest && found.Timestamp-timestamp <= timestamp-previous.Timestamp {
+		return found, nil
+	}
+
+	return previous, nil
+}
+
+// lastOfRun walks from first, the first of the blocks sharing its timestamp, to the last of them. Such runs are a few
+// blocks long, as blocks at most share a second.
+func lastOfRun(ctx context.Context, first BlockRef, timestampAt TimestampFunc) (BlockRef, error) {
+	last := first
+
+	for {
+		timestamp, err := timestampAt(ctx, last.Number+1)
+		// The head ends every run
+		if errors.Is(err, ErrBlockNotFound) {
+			return last, nil
+		}
+		if err != nil {
+			return BlockRef{}, fmt.Errorf("error getting block after %d: %v", last.Number, err)
+		}
+
+		if timestamp != first.Timestamp {
+			return last, nil
+		}
+
+		last = BlockRef{Number: last.Number + 1, Timestamp: timestamp}
+	}
+}
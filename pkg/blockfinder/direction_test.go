@@ -0,0 +1,48 @@
+package blockfinder
+
+import (
+	"context"
+	"testing"
+
+	"get-node-start-block/pkg/mockchain"
+)
+
+func TestPick(t *testing.T) {
+	// Four blocks share every timestamp: blocks 400 to 403 are stamped 100 seconds after genesis
+	chain := mockchain.Chain{GenesisTime: 1_600_000_000, BlockTime: 0.25, Height: 1000}
+	at := func(number int64) BlockRef { return BlockRef{Number: number, Timestamp: chain.Timestamp(number)} }
+
+	tests := []struct {
+		name      string
+		timestamp int64
+		direction Direction
+		want      BlockRef
+	}{
+		{name: "after picks the first of equal blocks", timestamp: chain.Timestamp(400), direction: After, want: at(400)},
+		{name: "before picks the last of equal blocks", timestamp: chain.Timestamp(400), direction: Before, want: at(403)},
+		{name: "closest picks the first of equal blocks", timestamp: chain.Timestamp(400), direction: Closest, want: at(400)},
+		{name: "before between blocks", timestamp: chain.Timestamp(400) - 1, direction: Before, want: at(399)},
+		{name: "before the last blocks of the chain", timestamp: chain.Timestamp(chain.Height), direction: Before, want: at(chain.Height)},
+		{name: "after the head", timestamp: chain.Timestamp(chain.Height) + 10, direction: Before, want: at(chain.Height)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var batched int
+
+			timestampAt, _ := fixtureTimestamps(chain, &batched)
+
+			// The block FindBlockByTimestamp returns, which is the head for timestamps after it
+			found := firstBlockAtOrAfter(chain, test.timestamp)
+
+			got, err := Pick(context.Background(), found, test.timestamp, test.direction, timestampAt)
+			if err != nil {
+				t.Fatalf("Pick() error = %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Pick() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
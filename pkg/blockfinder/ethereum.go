@@ -0,0 +1,256 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCClient is the subset of rpc.Client used to query EVM chains.
+type RPCClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// BatchRPCClient is an RPCClient that can also send several calls in a single request, like rpc.Client.
+type BatchRPCClient interface {
+	RPCClient
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// EthereumFinder finds blocks on EVM chains over JSON-RPC.
+type EthereumFinder struct {
+	cacheable
+
+	client RPCClient
+}
+
+var (
+	_ Finder          = (*EthereumFinder)(nil)
+	_ BatchChain      = (*EthereumFinder)(nil)
+	_ ChainIdentifier = (*EthereumFinder)(nil)
+	_ Finalizer       = (*EthereumFinder)(nil)
+)
+
+// quantity is a JSON-RPC quantity, like a block number, which must be a hex number that fits an int64.
+// Unlike hexutil.Big, it tells a null answer apart from zero.
+type quantity struct {
+	value *hexutil.Big
+}
+
+// rpcBlock is the part of the blocks of eth_getBlockByNumber read by the finder.
+type rpcBlock struct {
+	Number    *hexutil.Big `json:"number"`
+	Timestamp *hexutil.Big `json:"timestamp"`
+}
+
+// NewEthereumFinder creates an EthereumFinder using the given RPC client, such as an *rpc.Client or an *endpoint.Pool.
+// Clients implementing BatchRPCClient let the search fetch its last few blocks in a single request.
+func NewEthereumFinder(client RPCClient) *EthereumFinder {
+	return &EthereumFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *EthereumFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	number, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	timestampAt := f.cached(f.BlockTimestamp)
+
+	low, high, err := rangeEnds(ctx, 1, number, timestampAt)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return BatchSearch(ctx, low, high, timestamp, timestampAt, f.cachedBatch(f.BlockTimestamps))
+}
+
+// LatestHeight returns the number of the latest block.
+func (f *EthereumFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var number quantity
+	if err := f.client.CallContext(ctx, &number, "eth_blockNumber"); err != nil {
+		return 0, fmt.Errorf("error getting latest block number: %w", err)
+	}
+
+	if err := number.Validate(); err != nil {
+		return 0, fmt.Errorf("error getting latest block number: %w", err)
+	}
+
+	return number.Int64(), nil
+}
+
+// FinalizedHeight implements Finalizer with the finalized block tag, falling back to the safe one
+// on chains and clients that only track the latter.
+func (f *EthereumFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	var err error
+
+	for _, tag := range []string{"finalized", "safe"} {
+		var block *rpcBlock
+		if err = f.client.CallContext(ctx, &block, "eth_getBlockByNumber", tag, false); err == nil {
+			if block == nil {
+				err = fmt.Errorf("no %s block", tag)
+				continue
+			}
+
+			if err = block.Validate(); err == nil {
+				return block.Number.ToInt().Int64(), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("error getting finalized block: %w", err)
+}
+
+// ChainID implements ChainIdentifier.
+func (f *EthereumFinder) ChainID(ctx context.Context) (int64, error) {
+	var chainID quantity
+	if err := f.client.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return 0, fmt.Errorf("error getting chain ID: %w", err)
+	}
+
+	if err := chainID.Validate(); err != nil {
+		return 0, fmt.Errorf("error getting chain ID: %w", err)
+	}
+
+	return chainID.Int64(), nil
+}
+
+// BlockTimestamp returns the timestamp of the given block.
+func (f *EthereumFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var block *rpcBlock
+	err := f.client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(big.NewInt(number)), false)
+	if err != nil {
+		return 0, fmt.Errorf("error getting block %d: %w", number, err)
+	}
+
+	return block.timestampOf(number)
+}
+
+// BlockTimestamps returns the timestamps of the given blocks, fetched in a single batch request
+// if the client supports batches and one by one otherwise.
+func (f *EthereumFinder) BlockTimestamps(ctx context.Context, numbers []int64) ([]int64, error) {
+	timestamps := make([]int64, len(numbers))
+
+	batchClient, ok := f.client.(BatchRPCClient)
+	if !ok {
+		for index, number := range numbers {
+			timestamp, err := f.BlockTimestamp(ctx, number)
+			if err != nil {
+				return nil, err
+			}
+
+			timestamps[index] = timestamp
+		}
+
+		return timestamps, nil
+	}
+
+	blocks := make([]*rpcBlock, len(numbers))
+
+	batch := make([]rpc.BatchElem, len(numbers))
+	for index, number := range numbers {
+		batch[index] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeBig(big.NewInt(number)), false},
+			Result: &blocks[index],
+		}
+	}
+
+	if err := batchClient.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("error getting blocks %d to %d: %w", numbers[0], numbers[len(numbers)-1], err)
+	}
+
+	for index, number := range numbers {
+		if batch[index].Error != nil {
+			return nil, fmt.Errorf("error getting block %d: %w", number, batch[index].Error)
+		}
+
+		timestamp, err := blocks[index].timestampOf(number)
+		if err != nil {
+			return nil, err
+		}
+
+		timestamps[index] = timestamp
+	}
+
+	return timestamps, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, leaving q unset for null.
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	q.value = new(hexutil.Big)
+
+	return json.Unmarshal(data, q.value)
+}
+
+// Validate implements endpoint.Validator.
+func (q *quantity) Validate() error {
+	if err := validQuantity("value", q.value); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+
+	return nil
+}
+
+// Int64 returns the value of q, which must be valid.
+func (q *quantity) Int64() int64 {
+	return q.value.ToInt().Int64()
+}
+
+// Validate implements endpoint.Validator. A nil block, answered for blocks the endpoint doesn't have, is valid,
+// as it's up to the caller whether it's missing.
+func (b *rpcBlock) Validate() error {
+	if b == nil {
+		return nil
+	}
+
+	if err := validQuantity("number", b.Number); err != nil {
+		return fmt.Errorf("%w: block with %v", ErrMalformedResponse, err)
+	}
+
+	if err := validQuantity("timestamp", b.Timestamp); err != nil {
+		return fmt.Errorf("%w: block %d with %v", ErrMalformedResponse, b.Number.ToInt().Int64(), err)
+	}
+
+	return nil
+}
+
+// timestampOf returns the timestamp of b, the block number was asked for.
+func (b *rpcBlock) timestampOf(number int64) (int64, error) {
+	// Pruned nodes answer null for blocks they no longer have
+	if b == nil {
+		return 0, fmt.Errorf("block %d is unavailable", number)
+	}
+
+	if err := b.Validate(); err != nil {
+		return 0, err
+	}
+
+	// Endpoints behind a faulty load balancer may mix up the answers of concurrent requests
+	if actual := b.Number.ToInt().Int64(); actual != number {
+		return 0, fmt.Errorf("%w: asked for block %d, got block %d", ErrMalformedResponse, number, actual)
+	}
+
+	return b.Timestamp.ToInt().Int64(), nil
+}
+
+// validQuantity checks that the quantity named name was answered and fits an int64, describing it otherwise.
+func validQuantity(name string, value *hexutil.Big) error {
+	switch {
+	case value == nil:
+		return fmt.Errorf("no %s", name)
+	case value.ToInt().Sign() < 0 || !value.ToInt().IsInt64():
+		return fmt.Errorf("%s %s out of range", name, value.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EtherscanFinder reads blocks of EVM chains through an Etherscan-family explorer API, like Etherscan, its clones
+// on other chains or the compatible API of Blockscout. The endpoint URL is the API URL with the API key in its
+// query string, like https://api.etherscan.io/api?apikey=KEY. The explorer finds blocks by timestamp in a single
+// call, but its answers are only as good as its index, so they are best verified against the chain.
+type EtherscanFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Finder = (*EtherscanFinder)(nil)
+	_ Chain  = (*EtherscanFinder)(nil)
+)
+
+// NewEtherscanFinder creates an EtherscanFinder using the given HTTP client.
+func NewEtherscanFinder(client HTTPClient) *EtherscanFinder {
+	return &EtherscanFinder{client: client}
+}
+
+// etherscanResponse is the envelope of Etherscan API answers. Proxied JSON-RPC calls answer in JSON-RPC instead,
+// with an error object rather than a status.
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Validate implements the validation of answers by the endpoint pool, so that refused calls, like rate limited
+// ones, fail over and are retried.
+func (r *etherscanResponse) Validate() error {
+	switch {
+	case r.Error != nil:
+		return fmt.Errorf("explorer error: %s", r.Error.Message)
+	case r.Status == "0":
+		// Errors are in the result, as a string
+		var reason string
+		if err := json.Unmarshal(r.Result, &reason); err != nil || reason == "" {
+			reason = r.Message
+		}
+
+		return fmt.Errorf("explorer error: %s", reason)
+	case len(r.Result) == 0 || string(r.Result) == "null":
+		return fmt.Errorf("%w: explorer answer with no result", ErrMalformedResponse)
+	default:
+		return nil
+	}
+}
+
+// get calls the action of module with the given parameters and decodes its result into result.
+func (f *EtherscanFinder) get(ctx context.Context, module, action, parameters string, result interface{}) error {
+	var response etherscanResponse
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("?module=%s&action=%s%s", module, action, parameters), &response); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%w: %s result: %v", ErrMalformedResponse, action, err)
+	}
+
+	return nil
+}
+
+// FindBlockByTimestamp implements Finder, returning the first block at or after timestamp.
+func (f *EtherscanFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	number, err := f.BlockAfter(ctx, timestamp)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	blockTimestamp, err := f.BlockTimestamp(ctx, number)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return BlockRef{Number: number, Timestamp: blockTimestamp}, nil
+}
+
+// BlockAfter returns the number of the first block at or after timestamp, as indexed by the explorer.
+// Timestamps after the latest block have no such block and fail.
+func (f *EtherscanFinder) BlockAfter(ctx context.Context, timestamp int64) (int64, error) {
+	var result string
+	if err := f.get(ctx, "block", "getblocknobytime", fmt.Sprintf("&timestamp=%d&closest=after", timestamp), &result); err != nil {
+		return 0, fmt.Errorf("error getting block after %d: %v", timestamp, err)
+	}
+
+	number, err := strconv.ParseInt(result, 10, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("%w: invalid block number %q after %d", ErrMalformedResponse, result, timestamp)
+	}
+
+	return number, nil
+}
+
+// LatestHeight returns the number of the latest block known to the explorer.
+func (f *EtherscanFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var number hexutil.Big
+	if err := f.get(ctx, "proxy", "eth_blockNumber", "", &number); err != nil {
+		return 0, fmt.Errorf("error getting latest block number: %v", err)
+	}
+
+	if err := validQuantity("block number", &number); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+
+	return number.ToInt().Int64(), nil
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height, from its block reward, which is the
+// only block lookup both Etherscan and Blockscout serve without a JSON-RPC proxy.
+func (f *EtherscanFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var reward struct {
+		TimeStamp string `json:"timeStamp"`
+	}
+	if err := f.get(ctx, "block", "getblockreward", fmt.Sprintf("&blockno=%d", height), &reward); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	timestamp, err := strconv.ParseInt(reward.TimeStamp, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: block %d with invalid timestamp %q", ErrMalformedResponse, height, reward.TimeStamp)
+	}
+
+	return timestamp, nil
+}
+
+// BlockscoutFinder reads blocks of EVM chains through the REST API of a Blockscout explorer, whose endpoint URL
+// is the URL of the explorer, like https://eth.blockscout.com. It is searched by a ChainFinder.
+type BlockscoutFinder struct {
+	client HTTPClient
+}
+
+var _ Chain = (*BlockscoutFinder)(nil)
+
+// NewBlockscoutFinder creates a BlockscoutFinder using the given HTTP client.
+func NewBlockscoutFinder(client HTTPClient) *BlockscoutFinder {
+	return &BlockscoutFinder{client: client}
+}
+
+// LatestHeight returns the height of the latest block indexed by Blockscout.
+func (f *BlockscoutFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var blocks struct {
+		Items []struct {
+			Height int64 `json:"height"`
+		} `json:"items"`
+	}
+	if err := f.client.GetJSON(ctx, "api/v2/blocks?type=block", &blocks); err != nil {
+		return 0, fmt.Errorf("error getting latest blocks: %v", err)
+	}
+
+	if len(blocks.Items) == 0 {
+		return 0, fmt.Errorf("%w: no blocks indexed", ErrMalformedResponse)
+	}
+
+	return blocks.Items[0].Height, nil
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height.
+func (f *BlockscoutFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var block struct {
+		Height    int64  `json:"height"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("api/v2/blocks/%d", height), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", height, err)
+	}
+
+	if block.Height != height {
+		return 0, fmt.Errorf("%w: asked for block %d, got block %d", ErrMalformedResponse, height, block.Height)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, block.Timestamp)
+	if err != nil {
+		return 0, fmt.Errorf("%w: block %d with invalid timestamp %q", ErrMalformedResponse, height, block.Timestamp)
+	}
+
+	return timestamp.Unix(), nil
+}
@@ -0,0 +1,108 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// FarcasterEpoch is the Unix timestamp Farcaster time counts from, January 1, 2021 UTC.
+	FarcasterEpoch = 1609459200
+	// farcasterSequenceBits is the number of low bits of a hub event ID holding its sequence within a millisecond.
+	farcasterSequenceBits = 12
+)
+
+// ToFarcasterTime converts the Unix timestamp unix to Farcaster time, the seconds since FarcasterEpoch that
+// Farcaster messages carry their timestamps in.
+func ToFarcasterTime(unix int64) int64 {
+	return unix - FarcasterEpoch
+}
+
+// FromFarcasterTime converts the Farcaster time farcaster, in seconds since FarcasterEpoch, to a Unix timestamp.
+func FromFarcasterTime(farcaster int64) int64 {
+	return farcaster + FarcasterEpoch
+}
+
+// FarcasterFinder finds the Farcaster start point for a timestamp through the HTTP API of a Farcaster Hub.
+//
+// Farcaster has no blocks: the node starts from a Unix timestamp, so the returned BlockRef carries
+// the timestamp of the first hub event at or after the target as both its number and its timestamp.
+type FarcasterFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Finder = (*FarcasterFinder)(nil)
+	_ Chain  = (*FarcasterFinder)(nil)
+)
+
+// NewFarcasterFinder creates a FarcasterFinder using the given HTTP client.
+func NewFarcasterFinder(client HTTPClient) *FarcasterFinder {
+	return &FarcasterFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *FarcasterFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	if timestamp < FarcasterEpoch {
+		return BlockRef{}, fmt.Errorf("timestamp %d is before the Farcaster epoch", timestamp)
+	}
+
+	earliest, err := f.firstEventAfter(ctx, 0)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error getting earliest event: %v", err)
+	}
+
+	// Hubs prune their event log after a few days. Messages carry their own timestamps though,
+	// so when the log doesn't reach back to the target the target itself is the start point.
+	if earliest > timestamp {
+		return BlockRef{Number: timestamp, Timestamp: timestamp}, nil
+	}
+
+	first, err := f.firstEventAfter(ctx, timestamp)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error getting events after %d: %v", timestamp, err)
+	}
+
+	return BlockRef{Number: first, Timestamp: first}, nil
+}
+
+// LatestHeight returns the current time, as Farcaster start points are timestamps.
+func (f *FarcasterFinder) LatestHeight(_ context.Context) (int64, error) {
+	return time.Now().Unix(), nil
+}
+
+// BlockTimestamp returns number itself, as Farcaster start points are timestamps already.
+func (f *FarcasterFinder) BlockTimestamp(_ context.Context, number int64) (int64, error) {
+	return number, nil
+}
+
+// firstEventAfter returns the Unix timestamp of the first hub event at or after timestamp,
+// or of the earliest event the hub holds if timestamp is 0.
+func (f *FarcasterFinder) firstEventAfter(ctx context.Context, timestamp int64) (int64, error) {
+	path := "v1/events"
+	if timestamp > 0 {
+		// Event IDs are the Farcaster time of the event in milliseconds, followed by a sequence number
+		path = fmt.Sprintf("v1/events?from_event_id=%d", ToFarcasterTime(timestamp)*1000<<farcasterSequenceBits)
+	}
+
+	var response struct {
+		Events []struct {
+			ID int64 `json:"id"`
+		} `json:"events"`
+	}
+	if err := f.client.GetJSON(ctx, path, &response); err != nil {
+		return 0, err
+	}
+
+	if len(response.Events) == 0 {
+		// Nothing happened on the hub since the timestamp yet
+		if timestamp > 0 {
+			return timestamp, nil
+		}
+
+		return 0, fmt.Errorf("no events in response")
+	}
+
+	return FromFarcasterTime((response.Events[0].ID >> farcasterSequenceBits) / 1000), nil
+}
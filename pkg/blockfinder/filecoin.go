@@ -0,0 +1,133 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// FilecoinEpochDuration is the fixed time between two Filecoin epochs, in seconds.
+	FilecoinEpochDuration = 30
+	// FilecoinFinality is the number of epochs after which a Filecoin tipset can no longer be reorganized.
+	FilecoinFinality = 900
+)
+
+// FilecoinFinder finds epochs on Filecoin through the JSON-RPC API of a Lotus node.
+//
+// Epochs follow each other every FilecoinEpochDuration seconds from genesis, so the epoch of a timestamp is computed
+// rather than searched for, and checked against the tipset Lotus returns at that height. Null rounds, epochs
+// without any block, are still returned, as they are valid start epochs.
+type FilecoinFinder struct {
+	client RPCClient
+
+	genesisTime int64
+}
+
+var (
+	_ Finder    = (*FilecoinFinder)(nil)
+	_ Chain     = (*FilecoinFinder)(nil)
+	_ Finalizer = (*FilecoinFinder)(nil)
+)
+
+// NewFilecoinFinder creates a FilecoinFinder using the given RPC client.
+func NewFilecoinFinder(client RPCClient) *FilecoinFinder {
+	return &FilecoinFinder{client: client}
+}
+
+// filecoinTipSet is a tipset as returned by Lotus, with only the fields used here.
+type filecoinTipSet struct {
+	Height int64 `json:"Height"`
+	Blocks []struct {
+		Timestamp int64 `json:"Timestamp"`
+	} `json:"Blocks"`
+}
+
+// FindBlockByTimestamp implements Finder. The returned BlockRef holds an epoch.
+func (f *FilecoinFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	if err := f.loadGenesis(ctx); err != nil {
+		return BlockRef{}, err
+	}
+
+	head, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// The first epoch starting at or after the timestamp, rounding up
+	epoch := max((timestamp-f.genesisTime+FilecoinEpochDuration-1)/FilecoinEpochDuration, 0)
+	epoch = min(epoch, head)
+
+	if err := f.verifyEpoch(ctx, epoch); err != nil {
+		return BlockRef{}, err
+	}
+
+	return BlockRef{Number: epoch, Timestamp: f.epochTime(epoch)}, nil
+}
+
+// LatestHeight returns the epoch of the head tipset.
+func (f *FilecoinFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var head filecoinTipSet
+	if err := f.client.CallContext(ctx, &head, "Filecoin.ChainHead"); err != nil {
+		return 0, fmt.Errorf("error getting chain head: %v", err)
+	}
+
+	return head.Height, nil
+}
+
+// FinalizedHeight implements Finalizer, as tipsets are final after FilecoinFinality epochs.
+func (f *FilecoinFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, FilecoinFinality)
+}
+
+// BlockTimestamp returns the start time of the given epoch.
+func (f *FilecoinFinder) BlockTimestamp(ctx context.Context, epoch int64) (int64, error) {
+	if err := f.loadGenesis(ctx); err != nil {
+		return 0, err
+	}
+
+	return f.epochTime(epoch), nil
+}
+
+// verifyEpoch checks that the tipset of the node at epoch has the timestamp computed for it, which catches nodes
+// of another network. A null round returns the tipset of an earlier epoch, which is checked in its stead.
+func (f *FilecoinFinder) verifyEpoch(ctx context.Context, epoch int64) error {
+	var tipSet filecoinTipSet
+	if err := f.client.CallContext(ctx, &tipSet, "Filecoin.ChainGetTipSetByHeight", epoch, nil); err != nil {
+		return fmt.Errorf("error getting tipset at epoch %d: %v", epoch, err)
+	}
+
+	if len(tipSet.Blocks) == 0 {
+		return fmt.Errorf("tipset at epoch %d has no blocks", epoch)
+	}
+
+	if expected := f.epochTime(tipSet.Height); tipSet.Blocks[0].Timestamp != expected {
+		return fmt.Errorf("tipset at epoch %d has timestamp %d, expected %d from the epoch duration", tipSet.Height, tipSet.Blocks[0].Timestamp, expected)
+	}
+
+	return nil
+}
+
+// epochTime returns the start time of epoch.
+func (f *FilecoinFinder) epochTime(epoch int64) int64 {
+	return f.genesisTime + epoch*FilecoinEpochDuration
+}
+
+// loadGenesis fetches the timestamp of the genesis tipset, unless already known.
+func (f *FilecoinFinder) loadGenesis(ctx context.Context) error {
+	if f.genesisTime > 0 {
+		return nil
+	}
+
+	var genesis filecoinTipSet
+	if err := f.client.CallContext(ctx, &genesis, "Filecoin.ChainGetGenesis"); err != nil {
+		return fmt.Errorf("error getting genesis: %v", err)
+	}
+
+	if len(genesis.Blocks) == 0 {
+		return fmt.Errorf("genesis tipset has no blocks")
+	}
+
+	f.genesisTime = genesis.Blocks[0].Timestamp
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HederaFinder finds record file blocks on Hedera through the REST API of a mirror node, which looks blocks up
+// by consensus timestamp directly.
+type HederaFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Finder    = (*HederaFinder)(nil)
+	_ Chain     = (*HederaFinder)(nil)
+	_ Finalizer = (*HederaFinder)(nil)
+)
+
+// NewHederaFinder creates a HederaFinder using the given HTTP client.
+func NewHederaFinder(client HTTPClient) *HederaFinder {
+	return &HederaFinder{client: client}
+}
+
+// hederaBlock is a block of the mirror node API. Its consensus timestamps are seconds.nanoseconds strings.
+type hederaBlock struct {
+	Number    int64 `json:"number"`
+	Timestamp struct {
+		From string `json:"from"`
+	} `json:"timestamp"`
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *HederaFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	block, err := f.firstBlock(ctx, fmt.Sprintf("api/v1/blocks?timestamp=gte:%d.000000000&order=asc&limit=1", timestamp))
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// Nothing at or after the target yet, so the latest block is the closest
+	if block == nil {
+		if block, err = f.latestBlock(ctx); err != nil {
+			return BlockRef{}, err
+		}
+	}
+
+	return block.ref()
+}
+
+// LatestHeight returns the number of the latest block.
+func (f *HederaFinder) LatestHeight(ctx context.Context) (int64, error) {
+	block, err := f.latestBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return block.Number, nil
+}
+
+// FinalizedHeight implements Finalizer. Hedera transactions are final once they reach consensus,
+// so this is the latest block.
+func (f *HederaFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.LatestHeight(ctx)
+}
+
+// BlockTimestamp returns the consensus timestamp of the first transaction of the given block.
+func (f *HederaFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var block hederaBlock
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("api/v1/blocks/%d", number), &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", number, err)
+	}
+
+	ref, err := block.ref()
+	if err != nil {
+		return 0, err
+	}
+
+	return ref.Timestamp, nil
+}
+
+// latestBlock returns the latest block.
+func (f *HederaFinder) latestBlock(ctx context.Context) (*hederaBlock, error) {
+	block, err := f.firstBlock(ctx, "api/v1/blocks?order=desc&limit=1")
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, fmt.Errorf("error getting latest block: no blocks in response")
+	}
+
+	return block, nil
+}
+
+// firstBlock returns the first block listed at path, or nil if there is none.
+func (f *HederaFinder) firstBlock(ctx context.Context, path string) (*hederaBlock, error) {
+	var page struct {
+		Blocks []hederaBlock `json:"blocks"`
+	}
+	if err := f.client.GetJSON(ctx, path, &page); err != nil {
+		return nil, fmt.Errorf("error listing blocks: %v", err)
+	}
+
+	if len(page.Blocks) == 0 {
+		return nil, nil
+	}
+
+	return &page.Blocks[0], nil
+}
+
+// ref returns the number of b and the seconds of its consensus timestamp.
+func (b *hederaBlock) ref() (BlockRef, error) {
+	seconds, _, _ := strings.Cut(b.Timestamp.From, ".")
+
+	timestamp, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("error parsing timestamp of block %d: %v", b.Number, err)
+	}
+
+	return BlockRef{Number: b.Number, Timestamp: timestamp}, nil
+}
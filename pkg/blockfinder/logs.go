@@ -0,0 +1,134 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// initialLogWindow is the number of blocks of the first eth_getLogs range of FirstLogBlock.
+const initialLogWindow = 10_000
+
+// logRangeErrors are parts of the messages endpoints reject eth_getLogs ranges with, for spanning too many blocks
+// or matching too many logs. Every provider words these its own way.
+var logRangeErrors = []string{"block range", "range is too", "range too", "more than", "too many", "exceed", "response size", "too large"}
+
+// LogFinder is implemented by finders of chains with event logs, which can find the first block with a log
+// matching a filter.
+type LogFinder interface {
+	// FirstLogBlock returns the first block with a log emitted by address, whose topics start with topics.
+	// Empty topics match any topic.
+	FirstLogBlock(ctx context.Context, address string, topics []string) (BlockRef, error)
+}
+
+var (
+	_ LogFinder = (*EthereumFinder)(nil)
+	_ LogFinder = (*ChainFinder)(nil)
+)
+
+// FirstLogBlock implements LogFinder. Logs can't be emitted before the contract is deployed, so the search starts
+// at its deployment block and scans forward with eth_getLogs, doubling the range after every empty one and halving
+// it whenever the endpoint rejects it as too wide or matching too many logs.
+func (f *EthereumFinder) FirstLogBlock(ctx context.Context, address string, topics []string) (BlockRef, error) {
+	deployed, err := f.DeploymentBlock(ctx, address)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	latest, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	filterTopics := make([]interface{}, len(topics))
+	for index, topic := range topics {
+		if topic != "" {
+			filterTopics[index] = topic
+		}
+	}
+
+	window := int64(initialLogWindow)
+	for from := deployed.Number; from <= latest; {
+		to := min(from+window-1, latest)
+
+		var logs []struct {
+			BlockNumber *hexutil.Big `json:"blockNumber"`
+		}
+
+		err := f.client.CallContext(ctx, &logs, "eth_getLogs", map[string]interface{}{
+			"address":   address,
+			"topics":    filterTopics,
+			"fromBlock": hexutil.EncodeBig(big.NewInt(from)),
+			"toBlock":   hexutil.EncodeBig(big.NewInt(to)),
+		})
+		if IsLogRangeTooLarge(err) && window > 1 {
+			window /= 2
+			continue
+		}
+		if err != nil {
+			return BlockRef{}, fmt.Errorf("error getting logs of blocks %d to %d: %v", from, to, err)
+		}
+
+		if len(logs) == 0 {
+			from, window = to+1, window*2
+			continue
+		}
+
+		first := latest
+		for _, log := range logs {
+			if err := validQuantity("block number", log.BlockNumber); err != nil {
+				return BlockRef{}, fmt.Errorf("%w: log of blocks %d to %d with %v", ErrMalformedResponse, from, to, err)
+			}
+
+			first = min(first, log.BlockNumber.ToInt().Int64())
+		}
+
+		timestamp, err := f.BlockTimestamp(ctx, first)
+		if err != nil {
+			return BlockRef{}, err
+		}
+
+		return BlockRef{Number: first, Timestamp: timestamp}, nil
+	}
+
+	return BlockRef{}, fmt.Errorf("no logs of %s matching the topics as of block %d", address, latest)
+}
+
+// FirstLogBlock implements LogFinder, returning ErrNoContracts if the chain has no contracts.
+func (f *ChainFinder) FirstLogBlock(ctx context.Context, address string, topics []string) (BlockRef, error) {
+	finder, ok := f.chain.(LogFinder)
+	if !ok {
+		return BlockRef{}, ErrNoContracts
+	}
+
+	return finder.FirstLogBlock(ctx, address, topics)
+}
+
+// IsLogRangeTooLarge reports whether err is the answer of an endpoint refusing an eth_getLogs range
+// for spanning too many blocks or matching too many logs, rather than a failure to serve it.
+func IsLogRangeTooLarge(err error) bool {
+	var rpcError rpc.Error
+	if !errors.As(err, &rpcError) {
+		return false
+	}
+
+	message := strings.ToLower(rpcError.Error())
+
+	// Rate limits are worded like range limits, but are failures of the endpoint
+	if strings.Contains(message, "rate") {
+		return false
+	}
+
+	for _, part := range logRangeErrors {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+
+	return false
+}
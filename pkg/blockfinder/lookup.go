@@ -0,0 +1,152 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// BlockLookup is an indexer service answering which block of a chain was produced at a time in a single call,
+// for networks whose endpoints are slow to search or lack archive access. Its answers are hints: the block may be
+// the last one before the time rather than the first one at or after it, and indexers may be wrong, so they are
+// confirmed on the chain before being used.
+type BlockLookup interface {
+	// BlockAfter returns the number of the block at timestamp, ideally the first one at or after it.
+	BlockAfter(ctx context.Context, timestamp int64) (int64, error)
+}
+
+var (
+	_ BlockLookup = (*EtherscanFinder)(nil)
+	_ BlockLookup = (*MoralisLookup)(nil)
+	_ BlockLookup = (*CovalentLookup)(nil)
+	_ BlockLookup = (*SubgraphLookup)(nil)
+)
+
+// MoralisLookup looks blocks up with the dateToBlock API of Moralis. The endpoint URL is the API URL with the chain
+// in its query string, like https://deep-index.moralis.io/api/v2.2?chain=0x1, and the API key is sent in the
+// X-API-Key header.
+type MoralisLookup struct {
+	client HTTPClient
+}
+
+// NewMoralisLookup creates a MoralisLookup using the given HTTP client.
+func NewMoralisLookup(client HTTPClient) *MoralisLookup {
+	return &MoralisLookup{client: client}
+}
+
+// BlockAfter implements BlockLookup. Moralis answers the block closest to the timestamp, on either side of it.
+func (l *MoralisLookup) BlockAfter(ctx context.Context, timestamp int64) (int64, error) {
+	var block struct {
+		Block *int64 `json:"block"`
+	}
+	if err := l.client.GetJSON(ctx, fmt.Sprintf("dateToBlock?date=%d", timestamp), &block); err != nil {
+		return 0, fmt.Errorf("error getting block at %d: %v", timestamp, err)
+	}
+
+	if block.Block == nil || *block.Block < 0 {
+		return 0, fmt.Errorf("%w: no block at %d", ErrMalformedResponse, timestamp)
+	}
+
+	return *block.Block, nil
+}
+
+// covalentWindow is the time after the timestamp the blocks listed by Covalent are taken from. It only has to
+// hold a block, and the first one is picked.
+const covalentWindow = time.Hour
+
+// CovalentLookup looks blocks up with the block_v2 API of Covalent (GoldRush), listing the blocks of a time range.
+// The endpoint URL is the API URL of the chain with the API key in its query string, like
+// https://api.covalenthq.com/v1/1?key=KEY.
+type CovalentLookup struct {
+	client HTTPClient
+}
+
+// NewCovalentLookup creates a CovalentLookup using the given HTTP client.
+func NewCovalentLookup(client HTTPClient) *CovalentLookup {
+	return &CovalentLookup{client: client}
+}
+
+// BlockAfter implements BlockLookup, returning the first block Covalent lists from timestamp on.
+func (l *CovalentLookup) BlockAfter(ctx context.Context, timestamp int64) (int64, error) {
+	start := time.Unix(timestamp, 0).UTC()
+	end := start.Add(covalentWindow)
+
+	var response struct {
+		Data struct {
+			Items []struct {
+				Height int64 `json:"height"`
+			} `json:"items"`
+		} `json:"data"`
+		Error        bool   `json:"error"`
+		ErrorMessage string `json:"error_message"`
+	}
+
+	path := fmt.Sprintf("block_v2/%s/%s/?page-size=1", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err := l.client.GetJSON(ctx, path, &response); err != nil {
+		return 0, fmt.Errorf("error getting blocks after %d: %v", timestamp, err)
+	}
+
+	if response.Error {
+		return 0, fmt.Errorf("error getting blocks after %d: %s", timestamp, response.ErrorMessage)
+	}
+
+	if len(response.Data.Items) == 0 {
+		return 0, fmt.Errorf("no blocks within %s after %d", covalentWindow, timestamp)
+	}
+
+	return response.Data.Items[0].Height, nil
+}
+
+// SubgraphLookup looks blocks up in a blocks subgraph of The Graph, indexing the number and timestamp of every
+// block of a chain, like the ethereum-blocks subgraph. The endpoint URL is the GraphQL URL of the subgraph.
+type SubgraphLookup struct {
+	client HTTPClient
+}
+
+// NewSubgraphLookup creates a SubgraphLookup using the given HTTP client.
+func NewSubgraphLookup(client HTTPClient) *SubgraphLookup {
+	return &SubgraphLookup{client: client}
+}
+
+// subgraphQuery selects the first block at or after a timestamp in a blocks subgraph.
+const subgraphQuery = `query($timestamp: BigInt!) {
+  blocks(first: 1, orderBy: number, orderDirection: asc, where: {timestamp_gte: $timestamp}) { number }
+}`
+
+// BlockAfter implements BlockLookup.
+func (l *SubgraphLookup) BlockAfter(ctx context.Context, timestamp int64) (int64, error) {
+	request := map[string]interface{}{
+		"query":     subgraphQuery,
+		"variables": map[string]string{"timestamp": strconv.FormatInt(timestamp, 10)},
+	}
+
+	var response struct {
+		Data struct {
+			Blocks []struct {
+				Number string `json:"number"`
+			} `json:"blocks"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := l.client.PostJSON(ctx, "", request, &response); err != nil {
+		return 0, fmt.Errorf("error querying block after %d: %v", timestamp, err)
+	}
+
+	if len(response.Errors) > 0 {
+		return 0, fmt.Errorf("error querying block after %d: %s", timestamp, response.Errors[0].Message)
+	}
+
+	if len(response.Data.Blocks) == 0 {
+		return 0, fmt.Errorf("no block indexed after %d", timestamp)
+	}
+
+	number, err := strconv.ParseInt(response.Data.Blocks[0].Number, 10, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("%w: invalid block number %q", ErrMalformedResponse, response.Data.Blocks[0].Number)
+	}
+
+	return number, nil
+}
@@ -0,0 +1,128 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NearFinder finds blocks on NEAR Protocol over its JSON-RPC.
+//
+// NEAR skips heights whenever a block producer misses its slot, so lookups of those heights
+// report ErrBlockNotFound and the search moves on to neighboring heights.
+type NearFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder = (*NearFinder)(nil)
+	_ Chain  = (*NearFinder)(nil)
+)
+
+// NewNearFinder creates a NearFinder using the given HTTP client.
+func NewNearFinder(client HTTPClient) *NearFinder {
+	return &NearFinder{client: client}
+}
+
+// nearResponse is the JSON-RPC envelope of NEAR RPC responses.
+type nearResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Name  string `json:"name"`
+		Cause struct {
+			Name string `json:"name"`
+		} `json:"cause"`
+		Message string      `json:"message"`
+		Data    interface{} `json:"data"`
+	} `json:"error"`
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *NearFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	status, err := f.status(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// Non-archival nodes only keep the last few epochs
+	low := BlockRef{Number: status.SyncInfo.EarliestBlockHeight, Timestamp: status.SyncInfo.EarliestBlockTime.Unix()}
+	high := BlockRef{Number: status.SyncInfo.LatestBlockHeight, Timestamp: status.SyncInfo.LatestBlockTime.Unix()}
+
+	return Search(ctx, low, high, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the height of the latest block.
+func (f *NearFinder) LatestHeight(ctx context.Context) (int64, error) {
+	status, err := f.status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// BlockTimestamp returns the timestamp of the block at the given height, or ErrBlockNotFound if the height was skipped.
+func (f *NearFinder) BlockTimestamp(ctx context.Context, height int64) (int64, error) {
+	var block struct {
+		Header struct {
+			Timestamp int64 `json:"timestamp"` // Nanoseconds
+		} `json:"header"`
+	}
+	if err := f.call(ctx, "block", map[string]interface{}{"block_id": height}, &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %w", height, err)
+	}
+
+	return time.Unix(0, block.Header.Timestamp).Unix(), nil
+}
+
+// nearStatus is the result of the NEAR status method.
+type nearStatus struct {
+	SyncInfo struct {
+		LatestBlockHeight   int64     `json:"latest_block_height"`
+		LatestBlockTime     time.Time `json:"latest_block_time"`
+		EarliestBlockHeight int64     `json:"earliest_block_height"`
+		EarliestBlockTime   time.Time `json:"earliest_block_time"`
+	} `json:"sync_info"`
+}
+
+// status returns the sync status of the node.
+func (f *NearFinder) status(ctx context.Context) (*nearStatus, error) {
+	var status nearStatus
+	if err := f.call(ctx, "status", []interface{}{}, &status); err != nil {
+		return nil, fmt.Errorf("error getting status: %v", err)
+	}
+
+	return &status, nil
+}
+
+// call performs a NEAR JSON-RPC call. NEAR expects named params, which rpc.Client can't send.
+func (f *NearFinder) call(ctx context.Context, method string, params, result interface{}) error {
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "get-node-start-block",
+		"method":  method,
+		"params":  params,
+	}
+
+	var response nearResponse
+	if err := f.client.PostJSON(ctx, "", request, &response); err != nil {
+		return err
+	}
+
+	if response.Error != nil {
+		if response.Error.Cause.Name == "UNKNOWN_BLOCK" {
+			return ErrBlockNotFound
+		}
+
+		return fmt.Errorf("rpc error %s (%s): %s %v", response.Error.Name, response.Error.Cause.Name, response.Error.Message, response.Error.Data)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package blockfinder
+
+import (
+	"context"
+)
+
+// ProgressFunc is called by searches with the range of heights the block they look for is known to be in,
+// every time it narrows.
+type ProgressFunc func(low, high int64)
+
+// progressKey is the context key of the ProgressFunc of a search.
+type progressKey struct{}
+
+// WithProgress returns a copy of ctx whose searches report their progress to progress.
+func WithProgress(ctx context.Context, progress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// reportProgress reports the range of a search to the ProgressFunc of ctx, if it has one.
+func reportProgress(ctx context.Context, low, high int64) {
+	if progress, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		progress(low, high)
+	}
+}
@@ -0,0 +1,181 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Rollup is the stack of an optimistic rollup, which tells how its blocks are derived from the blocks of its L1.
+type Rollup string
+
+const (
+	// OPStack rollups, like Optimism and Base, record the L1 origin of every block in their L1Block predeploy.
+	OPStack Rollup = "op-stack"
+	// Arbitrum rollups map L1 blocks to the range of their blocks through the NodeInterface precompile.
+	Arbitrum Rollup = "arbitrum"
+)
+
+const (
+	// l1BlockAddress is the address of the L1Block predeploy of OP Stack rollups.
+	l1BlockAddress = "0x4200000000000000000000000000000000000015"
+	// l1BlockNumberSelector is the function selector of number() on the L1Block predeploy.
+	l1BlockNumberSelector = "0x8381f58a"
+	// nodeInterfaceAddress is the address of the NodeInterface precompile of Arbitrum rollups, only served
+	// through eth_call.
+	nodeInterfaceAddress = "0x00000000000000000000000000000000000000C8"
+	// l2BlockRangeForL1Selector is the function selector of l2BlockRangeForL1(uint64) on NodeInterface.
+	l2BlockRangeForL1Selector = "0x48e7f811"
+)
+
+// ErrNotRollup is returned by ChainFinder.L2BlockForL1 for chains that can't be rollups.
+var ErrNotRollup = errors.New("chain is not a rollup")
+
+// ParseRollup parses a rollup stack name.
+func ParseRollup(value string) (Rollup, error) {
+	switch rollup := Rollup(value); rollup {
+	case OPStack, Arbitrum:
+		return rollup, nil
+	default:
+		return "", fmt.Errorf("invalid rollup %q: must be op-stack or arbitrum", value)
+	}
+}
+
+// L1Mapper is implemented by finders of chains that may be rollups, which can map a block of their L1 to their own.
+type L1Mapper interface {
+	// L2BlockForL1 returns the first block of the rollup derived from the L1 block l1Number or a later one.
+	L2BlockForL1(ctx context.Context, rollup Rollup, l1Number int64) (BlockRef, error)
+}
+
+var (
+	_ L1Mapper = (*EthereumFinder)(nil)
+	_ L1Mapper = (*ChainFinder)(nil)
+)
+
+// L2BlockForL1 implements L1Mapper. Arbitrum rollups answer it in a single call, while the blocks of OP Stack rollups
+// are bisected on their L1 origin, which only grows from one block to the next. Both read the chain state, which
+// the endpoint must keep for old blocks.
+func (f *EthereumFinder) L2BlockForL1(ctx context.Context, rollup Rollup, l1Number int64) (BlockRef, error) {
+	var (
+		number int64
+		err    error
+	)
+
+	switch rollup {
+	case Arbitrum:
+		number, err = f.arbitrumBlockForL1(ctx, l1Number)
+	case OPStack:
+		number, err = f.opStackBlockForL1(ctx, l1Number)
+	default:
+		err = fmt.Errorf("unsupported rollup %q", rollup)
+	}
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	timestamp, err := f.cached(f.BlockTimestamp)(ctx, number)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return BlockRef{Number: number, Timestamp: timestamp}, nil
+}
+
+// arbitrumBlockForL1 returns the first block of the range NodeInterface maps the L1 block l1Number to.
+func (f *EthereumFinder) arbitrumBlockForL1(ctx context.Context, l1Number int64) (int64, error) {
+	call := map[string]interface{}{
+		"to":   nodeInterfaceAddress,
+		"data": l2BlockRangeForL1Selector + fmt.Sprintf("%064x", l1Number),
+	}
+
+	var result hexutil.Bytes
+	if err := f.client.CallContext(ctx, &result, "eth_call", call, "latest"); err != nil {
+		return 0, fmt.Errorf("error mapping L1 block %d: %v", l1Number, err)
+	}
+
+	// The range is two uint64 words, the first and the last block
+	if len(result) < 64 {
+		return 0, fmt.Errorf("%w: block range of L1 block %d is %d bytes long", ErrMalformedResponse, l1Number, len(result))
+	}
+
+	first := new(big.Int).SetBytes(result[:32])
+	if !first.IsInt64() {
+		return 0, fmt.Errorf("%w: block %s mapped to L1 block %d out of range", ErrMalformedResponse, first, l1Number)
+	}
+
+	return first.Int64(), nil
+}
+
+// opStackBlockForL1 bisects the blocks up to the latest one for the first whose L1 origin is l1Number or later.
+func (f *EthereumFinder) opStackBlockForL1(ctx context.Context, l1Number int64) (int64, error) {
+	latest, err := f.LatestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	origin, err := f.l1Origin(ctx, latest)
+	if err != nil {
+		return 0, err
+	}
+
+	if origin < l1Number {
+		return 0, fmt.Errorf("L1 block %d isn't derived yet, the latest block %d derives from L1 block %d", l1Number, latest, origin)
+	}
+
+	// Invariant: the L1 origin of high is l1Number or later, and the one of low is before it, the genesis block
+	// deriving from no L1 block the rollup reads
+	low, high := int64(0), latest
+	for high-low > 1 {
+		middle := low + (high-low)/2
+
+		if origin, err = f.l1Origin(ctx, middle); err != nil {
+			return 0, err
+		}
+
+		if origin >= l1Number {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+
+	return high, nil
+}
+
+// l1Origin returns the number of the L1 block the block at number of an OP Stack rollup derives from.
+func (f *EthereumFinder) l1Origin(ctx context.Context, number int64) (int64, error) {
+	call := map[string]interface{}{
+		"to":   l1BlockAddress,
+		"data": l1BlockNumberSelector,
+	}
+
+	var result hexutil.Bytes
+	if err := f.client.CallContext(ctx, &result, "eth_call", call, hexutil.EncodeBig(big.NewInt(number))); err != nil {
+		return 0, fmt.Errorf("error getting L1 origin of block %d: %v", number, err)
+	}
+
+	// Blocks from before the predeploy, like the legacy history of Optimism, come before any L1 origin
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	origin := new(big.Int).SetBytes(result)
+	if !origin.IsInt64() {
+		return 0, fmt.Errorf("%w: L1 origin %s of block %d out of range", ErrMalformedResponse, origin, number)
+	}
+
+	return origin.Int64(), nil
+}
+
+// L2BlockForL1 implements L1Mapper, returning ErrNotRollup if the chain can't be a rollup.
+func (f *ChainFinder) L2BlockForL1(ctx context.Context, rollup Rollup, l1Number int64) (BlockRef, error) {
+	mapper, ok := f.chain.(L1Mapper)
+	if !ok {
+		return BlockRef{}, ErrNotRollup
+	}
+
+	return mapper.L2BlockForL1(ctx, rollup, l1Number)
+}
@@ -0,0 +1,110 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Solana JSON-RPC error codes returned for slots without a block.
+const (
+	solanaBlockNotAvailable       = -32004
+	solanaSlotSkipped             = -32007
+	solanaLongTermStorageSlotSkip = -32009
+)
+
+// SolanaFinder finds slots on Solana over JSON-RPC. Block numbers of the returned BlockRefs are slots.
+type SolanaFinder struct {
+	cacheable
+
+	client RPCClient
+}
+
+var (
+	_ Finder = (*SolanaFinder)(nil)
+	_ Chain  = (*SolanaFinder)(nil)
+)
+
+// NewSolanaFinder creates a SolanaFinder using the given RPC client.
+func NewSolanaFinder(client RPCClient) *SolanaFinder {
+	return &SolanaFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *SolanaFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	latestSlot, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	var firstSlot int64
+	if err := f.client.CallContext(ctx, &firstSlot, "getFirstAvailableBlock"); err != nil {
+		return BlockRef{}, fmt.Errorf("error getting first available slot: %v", err)
+	}
+
+	timestampAt := f.cached(f.BlockTimestamp)
+
+	low, found, err := nearestBlock(ctx, firstSlot, latestSlot, timestampAt)
+	if err != nil {
+		return BlockRef{}, err
+	}
+	if !found {
+		return BlockRef{}, fmt.Errorf("no block between slots %d and %d", firstSlot, latestSlot)
+	}
+
+	high, found, err := nearestBlock(ctx, latestSlot, low.Number, timestampAt)
+	if err != nil {
+		return BlockRef{}, err
+	}
+	if !found {
+		high = low
+	}
+
+	return Search(ctx, low, high, timestamp, timestampAt)
+}
+
+// LatestHeight returns the latest slot, which may not have a block.
+func (f *SolanaFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var slot int64
+	if err := f.client.CallContext(ctx, &slot, "getSlot"); err != nil {
+		return 0, fmt.Errorf("error getting latest slot: %v", err)
+	}
+
+	return slot, nil
+}
+
+// BlockTimestamp returns the timestamp of the block produced in the given slot,
+// or ErrBlockNotFound if the slot was skipped.
+func (f *SolanaFinder) BlockTimestamp(ctx context.Context, slot int64) (int64, error) {
+	var blockTime *int64
+
+	err := f.client.CallContext(ctx, &blockTime, "getBlockTime", slot)
+	if IsSolanaSlotSkipped(err) {
+		return 0, fmt.Errorf("slot %d: %w", slot, ErrBlockNotFound)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error getting block time of slot %d: %v", slot, err)
+	}
+	if blockTime == nil {
+		return 0, fmt.Errorf("slot %d: %w", slot, ErrBlockNotFound)
+	}
+
+	return *blockTime, nil
+}
+
+// IsSolanaSlotSkipped reports whether err is the answer of a Solana node for a slot without a block.
+func IsSolanaSlotSkipped(err error) bool {
+	var rpcError rpc.Error
+	if !errors.As(err, &rpcError) {
+		return false
+	}
+
+	switch rpcError.ErrorCode() {
+	case solanaBlockNotAvailable, solanaSlotSkipped, solanaLongTermStorageSlotSkip:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// speculationKey is the context key marking searches as speculative.
+type speculationKey struct{}
+
+// WithSpeculation returns a copy of ctx whose searches speculate: whenever a search bisects its range, it fetches
+// the midpoints of both halves along with the midpoint of the range, in parallel, and bisects again with the one
+// of the half the block is in. Two levels of bisection then take the time of a single call, for one more call.
+// Steps interpolating the target are left as they are, as where they go next depends on the answer.
+func WithSpeculation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, speculationKey{}, true)
+}
+
+// speculating returns whether the searches of ctx speculate.
+func speculating(ctx context.Context) bool {
+	speculative, _ := ctx.Value(speculationKey{}).(bool)
+
+	return speculative
+}
+
+// prefetched is the outcome of fetching the timestamp of a block ahead of the search.
+type prefetched struct {
+	timestamp int64
+	err       error
+}
+
+// prefetcher fetches the timestamps of blocks a search may need next in parallel, and serves them once it does.
+type prefetcher struct {
+	timestampAt TimestampFunc
+
+	locker  sync.Mutex
+	fetched map[int64]prefetched
+}
+
+// newPrefetcher creates a prefetcher fetching timestamps with timestampAt.
+func newPrefetcher(timestampAt TimestampFunc) *prefetcher {
+	return &prefetcher{timestampAt: timestampAt, fetched: make(map[int64]prefetched)}
+}
+
+// TimestampAt is a TimestampFunc serving prefetched timestamps, and fetching the other ones.
+func (p *prefetcher) TimestampAt(ctx context.Context, height int64) (int64, error) {
+	p.locker.Lock()
+	result, ok := p.fetched[height]
+	p.locker.Unlock()
+
+	if ok {
+		return result.timestamp, result.err
+	}
+
+	return p.timestampAt(ctx, height)
+}
+
+// prefetch fetches the timestamps of the blocks at heights at once. Failures other than missing blocks aren't kept,
+// so that the search fetches those blocks again if it needs them, with the retries of the client.
+func (p *prefetcher) prefetch(ctx context.Context, heights ...int64) {
+	var wg sync.WaitGroup
+
+	for _, height := range heights {
+		p.locker.Lock()
+		_, ok := p.fetched[height]
+		p.locker.Unlock()
+
+		if ok {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(height int64) {
+			defer wg.Done()
+
+			timestamp, err := p.timestampAt(ctx, height)
+			if err != nil && !errors.Is(err, ErrBlockNotFound) {
+				return
+			}
+
+			p.locker.Lock()
+			p.fetched[height] = prefetched{timestamp: timestamp, err: err}
+			p.locker.Unlock()
+		}(height)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,45 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// StarknetFinder reads Starknet blocks over the Starknet JSON-RPC API. It is searched by a ChainFinder.
+type StarknetFinder struct {
+	client RPCClient
+}
+
+var _ Chain = (*StarknetFinder)(nil)
+
+// NewStarknetFinder creates a StarknetFinder using the given RPC client.
+func NewStarknetFinder(client RPCClient) *StarknetFinder {
+	return &StarknetFinder{client: client}
+}
+
+// starknetBlockID identifies a block by number in Starknet JSON-RPC requests.
+type starknetBlockID struct {
+	BlockNumber int64 `json:"block_number"`
+}
+
+// LatestHeight returns the number of the latest accepted block.
+func (f *StarknetFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var number int64
+	if err := f.client.CallContext(ctx, &number, "starknet_blockNumber"); err != nil {
+		return 0, fmt.Errorf("error getting latest block number: %v", err)
+	}
+
+	return number, nil
+}
+
+// BlockTimestamp returns the timestamp of the block with the given number.
+func (f *StarknetFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var block struct {
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := f.client.CallContext(ctx, &block, "starknet_getBlockWithTxHashes", starknetBlockID{BlockNumber: number}); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", number, err)
+	}
+
+	return block.Timestamp, nil
+}
@@ -0,0 +1,79 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StellarFinder finds ledgers on Stellar through a Horizon server. Heights are ledger sequence numbers.
+type StellarFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder    = (*StellarFinder)(nil)
+	_ Chain     = (*StellarFinder)(nil)
+	_ Finalizer = (*StellarFinder)(nil)
+)
+
+// NewStellarFinder creates a StellarFinder using the given HTTP client.
+func NewStellarFinder(client HTTPClient) *StellarFinder {
+	return &StellarFinder{client: client}
+}
+
+// horizonRoot is the state of a Horizon server, as returned by its root endpoint.
+type horizonRoot struct {
+	HistoryLatestLedger int64 `json:"history_latest_ledger"`
+	HistoryElderLedger  int64 `json:"history_elder_ledger"`
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *StellarFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	root, err := f.root(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// Horizon only serves the ledgers it ingested, from its elder ledger on. The genesis ledger closed at 0.
+	return searchRange(ctx, max(root.HistoryElderLedger, 2), root.HistoryLatestLedger, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the sequence number of the latest ledger ingested by Horizon.
+func (f *StellarFinder) LatestHeight(ctx context.Context) (int64, error) {
+	root, err := f.root(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return root.HistoryLatestLedger, nil
+}
+
+// FinalizedHeight implements Finalizer. Stellar ledgers are final once closed, so this is the latest ledger.
+func (f *StellarFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.LatestHeight(ctx)
+}
+
+// BlockTimestamp returns the close time of the ledger with the given sequence number.
+func (f *StellarFinder) BlockTimestamp(ctx context.Context, sequence int64) (int64, error) {
+	var ledger struct {
+		ClosedAt time.Time `json:"closed_at"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("ledgers/%d", sequence), &ledger); err != nil {
+		return 0, fmt.Errorf("error getting ledger %d: %v", sequence, err)
+	}
+
+	return ledger.ClosedAt.Unix(), nil
+}
+
+// root returns the state of the Horizon server.
+func (f *StellarFinder) root(ctx context.Context) (*horizonRoot, error) {
+	var root horizonRoot
+	if err := f.client.GetJSON(ctx, "", &root); err != nil {
+		return nil, fmt.Errorf("error getting horizon status: %v", err)
+	}
+
+	return &root, nil
+}
@@ -0,0 +1,156 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// substrateTimestampNowKey is the storage key of Timestamp::Now, twox128("Timestamp") ++ twox128("Now"),
+// which the timestamp.set inherent of every block writes in milliseconds.
+const substrateTimestampNowKey = "0xf0c365c3cf59d671eb72da0e7a4113c49f1f0515f462cdcf84e0f1d6045dfcbb"
+
+// SubstrateFinder finds blocks on Substrate chains such as Polkadot, Kusama and their parachains,
+// reading the Timestamp pallet storage over the node's JSON-RPC.
+type SubstrateFinder struct {
+	cacheable
+
+	client RPCClient
+}
+
+var (
+	_ Finder    = (*SubstrateFinder)(nil)
+	_ Chain     = (*SubstrateFinder)(nil)
+	_ Finalizer = (*SubstrateFinder)(nil)
+)
+
+// NewSubstrateFinder creates a SubstrateFinder using the given RPC client.
+func NewSubstrateFinder(client RPCClient) *SubstrateFinder {
+	return &SubstrateFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *SubstrateFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	number, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	// The genesis block has no timestamp, as it isn't produced by an author
+	return searchRange(ctx, 1, number, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the number of the best block.
+func (f *SubstrateFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var header struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := f.client.CallContext(ctx, &header, "chain_getHeader"); err != nil {
+		return 0, fmt.Errorf("error getting latest header: %v", err)
+	}
+
+	return int64(header.Number), nil
+}
+
+// FinalizedHeight implements Finalizer with the number of the block last finalized by GRANDPA.
+func (f *SubstrateFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	var hash string
+	if err := f.client.CallContext(ctx, &hash, "chain_getFinalizedHead"); err != nil {
+		return 0, fmt.Errorf("error getting finalized head: %v", err)
+	}
+
+	var header struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := f.client.CallContext(ctx, &header, "chain_getHeader", hash); err != nil {
+		return 0, fmt.Errorf("error getting finalized header: %v", err)
+	}
+
+	return int64(header.Number), nil
+}
+
+// BlockTimestamp returns the timestamp of the block with the given number.
+func (f *SubstrateFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var hash *string
+	if err := f.client.CallContext(ctx, &hash, "chain_getBlockHash", number); err != nil {
+		return 0, fmt.Errorf("error getting hash of block %d: %v", number, err)
+	}
+	if hash == nil {
+		return 0, fmt.Errorf("error getting hash of block %d: %w", number, ErrBlockNotFound)
+	}
+
+	var storage *hexutil.Bytes
+	if err := f.client.CallContext(ctx, &storage, "state_getStorage", substrateTimestampNowKey, *hash); err != nil {
+		return 0, fmt.Errorf("error getting timestamp of block %d: %v", number, err)
+	}
+	if storage == nil || len(*storage) != 8 {
+		return 0, fmt.Errorf("error getting timestamp of block %d: unexpected storage value", number)
+	}
+
+	// SCALE encodes the u64 milliseconds little-endian
+	return int64(binary.LittleEndian.Uint64(*storage) / 1000), nil
+}
+
+// SidecarFinder finds blocks on Substrate chains through the REST API of a Substrate API Sidecar.
+type SidecarFinder struct {
+	cacheable
+
+	client HTTPClient
+}
+
+var (
+	_ Finder = (*SidecarFinder)(nil)
+	_ Chain  = (*SidecarFinder)(nil)
+)
+
+// NewSidecarFinder creates a SidecarFinder using the given HTTP client.
+func NewSidecarFinder(client HTTPClient) *SidecarFinder {
+	return &SidecarFinder{client: client}
+}
+
+// FindBlockByTimestamp implements Finder.
+func (f *SidecarFinder) FindBlockByTimestamp(ctx context.Context, timestamp int64) (BlockRef, error) {
+	number, err := f.LatestHeight(ctx)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return searchRange(ctx, 1, number, timestamp, f.cached(f.BlockTimestamp))
+}
+
+// LatestHeight returns the number of the best block.
+func (f *SidecarFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var header struct {
+		Number string `json:"number"`
+	}
+	if err := f.client.GetJSON(ctx, "blocks/head/header", &header); err != nil {
+		return 0, fmt.Errorf("error getting latest header: %v", err)
+	}
+
+	number, err := strconv.ParseInt(header.Number, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing latest block number: %v", err)
+	}
+
+	return number, nil
+}
+
+// BlockTimestamp returns the timestamp of the block with the given number.
+func (f *SidecarFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var storage struct {
+		Value string `json:"value"`
+	}
+	if err := f.client.GetJSON(ctx, fmt.Sprintf("pallets/timestamp/storage/now?at=%d", number), &storage); err != nil {
+		return 0, fmt.Errorf("error getting timestamp of block %d: %v", number, err)
+	}
+
+	milliseconds, err := strconv.ParseInt(storage.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing timestamp of block %d: %v", number, err)
+	}
+
+	return milliseconds / 1000, nil
+}
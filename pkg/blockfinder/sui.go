@@ -0,0 +1,54 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// SuiFinder reads Sui checkpoints over JSON-RPC. Sui has no blocks, so heights are checkpoint sequence numbers,
+// which Sui indexers start from. It is searched by a ChainFinder.
+type SuiFinder struct {
+	client RPCClient
+}
+
+var _ Chain = (*SuiFinder)(nil)
+
+// NewSuiFinder creates a SuiFinder using the given RPC client.
+func NewSuiFinder(client RPCClient) *SuiFinder {
+	return &SuiFinder{client: client}
+}
+
+// LatestHeight returns the sequence number of the latest checkpoint.
+func (f *SuiFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var sequenceNumber string
+	if err := f.client.CallContext(ctx, &sequenceNumber, "sui_getLatestCheckpointSequenceNumber"); err != nil {
+		return 0, fmt.Errorf("error getting latest checkpoint: %v", err)
+	}
+
+	latest, err := strconv.ParseInt(sequenceNumber, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing latest checkpoint: %v", err)
+	}
+
+	return latest, nil
+}
+
+// BlockTimestamp returns the timestamp of the checkpoint with the given sequence number.
+func (f *SuiFinder) BlockTimestamp(ctx context.Context, sequenceNumber int64) (int64, error) {
+	var checkpoint struct {
+		TimestampMs string `json:"timestampMs"`
+	}
+
+	// Checkpoint IDs are sequence numbers encoded as strings, or digests
+	if err := f.client.CallContext(ctx, &checkpoint, "sui_getCheckpoint", strconv.FormatInt(sequenceNumber, 10)); err != nil {
+		return 0, fmt.Errorf("error getting checkpoint %d: %v", sequenceNumber, err)
+	}
+
+	milliseconds, err := strconv.ParseInt(checkpoint.TimestampMs, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing timestamp of checkpoint %d: %v", sequenceNumber, err)
+	}
+
+	return milliseconds / 1000, nil
+}
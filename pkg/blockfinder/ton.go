@@ -0,0 +1,77 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// tonMasterchainShard is the shard of the masterchain, the whole workchain -1, as a signed 64-bit integer.
+const tonMasterchainShard = "-9223372036854775808"
+
+// TonFinder reads TON masterchain blocks through the toncenter HTTP API, also served by self-hosted ton-http-api
+// instances in front of liteservers. Heights are masterchain seqnos. It is searched by a ChainFinder.
+type TonFinder struct {
+	client HTTPClient
+}
+
+var _ Chain = (*TonFinder)(nil)
+
+// NewTonFinder creates a TonFinder using the given HTTP client.
+func NewTonFinder(client HTTPClient) *TonFinder {
+	return &TonFinder{client: client}
+}
+
+// tonResponse is the envelope of toncenter API responses.
+type tonResponse struct {
+	OK     bool            `json:"ok"`
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+	Code   int             `json:"code"`
+}
+
+// LatestHeight returns the seqno of the latest masterchain block.
+func (f *TonFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var info struct {
+		Last struct {
+			Seqno int64 `json:"seqno"`
+		} `json:"last"`
+	}
+	if err := f.get(ctx, "api/v2/getMasterchainInfo", &info); err != nil {
+		return 0, fmt.Errorf("error getting masterchain info: %v", err)
+	}
+
+	return info.Last.Seqno, nil
+}
+
+// BlockTimestamp returns the generation time of the masterchain block with the given seqno.
+func (f *TonFinder) BlockTimestamp(ctx context.Context, seqno int64) (int64, error) {
+	var header struct {
+		GenUtime int64 `json:"gen_utime"`
+	}
+
+	path := fmt.Sprintf("api/v2/getBlockHeader?workchain=-1&shard=%s&seqno=%d", tonMasterchainShard, seqno)
+	if err := f.get(ctx, path, &header); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", seqno, err)
+	}
+
+	return header.GenUtime, nil
+}
+
+// get sends a GET request for path and decodes the result of the response into result.
+func (f *TonFinder) get(ctx context.Context, path string, result interface{}) error {
+	var response tonResponse
+	if err := f.client.GetJSON(ctx, path, &response); err != nil {
+		return err
+	}
+
+	if !response.OK {
+		return fmt.Errorf("api error %d: %s", response.Code, response.Error)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+)
+
+// TronConfirmations is the number of blocks produced on top of a TRON block before it is solidified,
+// which takes two thirds of its 27 super representatives.
+const TronConfirmations = 19
+
+// TronFinder reads TRON blocks through the HTTP API of a java-tron full node, such as TronGrid.
+// It is searched by a ChainFinder, as TRON produces a block every three seconds like an EVM chain.
+type TronFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Chain     = (*TronFinder)(nil)
+	_ Finalizer = (*TronFinder)(nil)
+)
+
+// NewTronFinder creates a TronFinder using the given HTTP client.
+func NewTronFinder(client HTTPClient) *TronFinder {
+	return &TronFinder{client: client}
+}
+
+// tronBlock is a block as returned by the wallet API. Blocks that don't exist are returned as an empty object.
+type tronBlock struct {
+	BlockID     string `json:"blockID"`
+	BlockHeader struct {
+		RawData struct {
+			Number    int64 `json:"number"`
+			Timestamp int64 `json:"timestamp"` // Milliseconds
+		} `json:"raw_data"`
+	} `json:"block_header"`
+}
+
+// LatestHeight returns the number of the latest block.
+func (f *TronFinder) LatestHeight(ctx context.Context) (int64, error) {
+	var block tronBlock
+	if err := f.client.PostJSON(ctx, "wallet/getnowblock", struct{}{}, &block); err != nil {
+		return 0, fmt.Errorf("error getting latest block: %v", err)
+	}
+
+	return block.BlockHeader.RawData.Number, nil
+}
+
+// FinalizedHeight implements Finalizer with the latest block solidified after TronConfirmations.
+func (f *TronFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return confirmedHeight(ctx, f, TronConfirmations)
+}
+
+// BlockTimestamp returns the timestamp of the block with the given number.
+func (f *TronFinder) BlockTimestamp(ctx context.Context, number int64) (int64, error) {
+	var block tronBlock
+	if err := f.client.PostJSON(ctx, "wallet/getblockbynum", map[string]int64{"num": number}, &block); err != nil {
+		return 0, fmt.Errorf("error getting block %d: %v", number, err)
+	}
+
+	if block.BlockID == "" {
+		return 0, fmt.Errorf("block %d is unavailable", number)
+	}
+
+	return block.BlockHeader.RawData.Timestamp / 1000, nil
+}
@@ -0,0 +1,107 @@
+package blockfinder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// VSLSettlementAddress is the address of the Settlement contract on the RSS3 VSL mainnet, which tracks epochs.
+	VSLSettlementAddress = "0x0cE3159BF19F3C55B648D04E8f0Ae1Ae118D2A0B"
+	// settlementCurrentEpochSelector is the function selector of currentEpoch() on the Settlement contract.
+	settlementCurrentEpochSelector = "0x76671808"
+)
+
+// EpochAligner is implemented by finders of chains organized in epochs, whose start blocks should be epoch starts.
+type EpochAligner interface {
+	// AlignToEpoch returns the first block of the epoch block belongs to.
+	AlignToEpoch(ctx context.Context, block BlockRef) (BlockRef, error)
+}
+
+// VSLFinder finds blocks on the RSS3 Value Sublayer like an EthereumFinder, and aligns them to the epochs
+// tracked by its Settlement contract, so that nodes start indexing exactly at an epoch start.
+type VSLFinder struct {
+	*ChainFinder
+
+	client     RPCClient
+	settlement string
+}
+
+var (
+	_ Finder       = (*VSLFinder)(nil)
+	_ EpochAligner = (*VSLFinder)(nil)
+)
+
+// NewVSLFinder creates a VSLFinder using the given RPC client and the Settlement contract at settlement.
+func NewVSLFinder(client RPCClient, settlement string) *VSLFinder {
+	return &VSLFinder{
+		ChainFinder: NewChainFinder(NewEthereumFinder(client), 1),
+		client:      client,
+		settlement:  settlement,
+	}
+}
+
+// AlignToEpoch implements EpochAligner. The epoch only ever grows from one block to the next,
+// so the first block of an epoch is found by bisecting the blocks up to block.
+func (f *VSLFinder) AlignToEpoch(ctx context.Context, block BlockRef) (BlockRef, error) {
+	epoch, err := f.Epoch(ctx, block.Number)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	low, high := int64(1), block.Number
+	for low < high {
+		middle := low + (high-low)/2
+
+		middleEpoch, err := f.Epoch(ctx, middle)
+		if err != nil {
+			return BlockRef{}, err
+		}
+
+		if middleEpoch >= epoch {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+
+	if high == block.Number {
+		return block, nil
+	}
+
+	timestamp, err := f.cached(f.BlockTimestamp)(ctx, high)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	return BlockRef{Number: high, Timestamp: timestamp}, nil
+}
+
+// Epoch returns the epoch the Settlement contract was in at the given block. This needs the chain state
+// of past blocks, so the endpoint must be an archive node.
+func (f *VSLFinder) Epoch(ctx context.Context, number int64) (int64, error) {
+	call := map[string]interface{}{
+		"to":   f.settlement,
+		"data": settlementCurrentEpochSelector,
+	}
+
+	var result hexutil.Bytes
+	if err := f.client.CallContext(ctx, &result, "eth_call", call, hexutil.EncodeBig(big.NewInt(number))); err != nil {
+		return 0, fmt.Errorf("error getting epoch at block %d: %v", number, err)
+	}
+
+	// The contract didn't exist yet, which is before the first epoch
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	epoch := new(big.Int).SetBytes(result)
+	if !epoch.IsInt64() {
+		return 0, fmt.Errorf("%w: epoch %s at block %d out of range", ErrMalformedResponse, epoch, number)
+	}
+
+	return epoch.Int64(), nil
+}
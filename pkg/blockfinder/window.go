@@ -0,0 +1,98 @@
+package blockfinder
+
+import (
+	"context"
+	"errors"
+)
+
+// scanWindow settles the block found by searching from low to high for timestamp on chains whose timestamps aren't
+// monotonic, checking the blocks up to window blocks on both sides of it. A block stamped ahead of its successors
+// makes the search stop at a crossing of the target that the chain falls back from, possibly hundreds of blocks
+// before the crossing the chain stays past. The block returned follows the last block of the window stamped before
+// the target, and when that is the edge of the window, the search goes on past it, so that the chain is at or past
+// the target from the returned block on.
+func scanWindow(ctx context.Context, low, high, found BlockRef, timestamp, window int64,
+	timestampAt TimestampFunc, timestampsAt BatchTimestampFunc,
+) (BlockRef, error) {
+	timestampAt, timestampsAt = checkedTimestamps(timestampAt, timestampsAt)
+
+	for {
+		// Targets before the first block or after the head have nothing to settle
+		if found.Number == low.Number || found.Timestamp < timestamp {
+			return found, nil
+		}
+
+		blocks, err := windowBlocks(ctx, max(low.Number+1, found.Number-window), min(high.Number, found.Number+window), timestampAt, timestampsAt)
+		if err != nil {
+			return BlockRef{}, err
+		}
+
+		last := -1
+		for index, block := range blocks {
+			if block.Timestamp < timestamp {
+				last = index
+			}
+		}
+
+		switch {
+		case last == -1 && blocks[0].Number == low.Number+1:
+			// low is the last block before the target
+			return blocks[0], nil
+		case last == -1:
+			// The whole window is past the target, so the chain crossed it earlier
+			high = blocks[0]
+		case last == len(blocks)-1 && blocks[last].Number < high.Number:
+			// The chain is still before the target at the end of the window, so it crosses it later
+			low = blocks[last]
+		case last == len(blocks)-1:
+			return found, nil
+		default:
+			return blocks[last+1], nil
+		}
+
+		if found, err = BatchSearch(ctx, low, high, timestamp, timestampAt, timestampsAt); err != nil {
+			return BlockRef{}, err
+		}
+	}
+}
+
+// windowBlocks returns the blocks from height first to height last, both included, leaving out the heights without
+// a block. There is at least one block, as the block found by the search is in the window.
+func windowBlocks(ctx context.Context, first, last int64, timestampAt TimestampFunc, timestampsAt BatchTimestampFunc) ([]BlockRef, error) {
+	heights := make([]int64, 0, last-first+1)
+	for height := first; height <= last; height++ {
+		heights = append(heights, height)
+	}
+
+	blocks := make([]BlockRef, 0, len(heights))
+
+	// Chains skipping heights fail batches including them, so these are fetched one by one
+	if timestampsAt != nil {
+		timestamps, err := timestampsAt(ctx, heights)
+		if err == nil {
+			for index, height := range heights {
+				blocks = append(blocks, BlockRef{Number: height, Timestamp: timestamps[index]})
+			}
+
+			return blocks, nil
+		}
+
+		if !errors.Is(err, ErrBlockNotFound) {
+			return nil, err
+		}
+	}
+
+	for _, height := range heights {
+		timestamp, err := timestampAt(ctx, height)
+		if errors.Is(err, ErrBlockNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, BlockRef{Number: height, Timestamp: timestamp})
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,92 @@
+package blockfinder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// rippleEpoch is the Unix timestamp of 2000-01-01, from which the XRP Ledger counts its close times.
+const rippleEpoch = 946684800
+
+// XRPLFinder reads ledgers of the XRP Ledger through the JSON-RPC API of rippled. Heights are ledger indexes,
+// and the latest height is the latest validated ledger. It is searched by a ChainFinder.
+type XRPLFinder struct {
+	client HTTPClient
+}
+
+var (
+	_ Chain     = (*XRPLFinder)(nil)
+	_ Finalizer = (*XRPLFinder)(nil)
+)
+
+// NewXRPLFinder creates an XRPLFinder using the given HTTP client.
+func NewXRPLFinder(client HTTPClient) *XRPLFinder {
+	return &XRPLFinder{client: client}
+}
+
+// xrplResponse is the envelope of rippled responses. Errors are reported in the result.
+type xrplResponse struct {
+	Result json.RawMessage `json:"result"`
+}
+
+// xrplLedgerResult is the result of the ledger method.
+type xrplLedgerResult struct {
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	ErrorMessage string `json:"error_message"`
+	LedgerIndex  int64  `json:"ledger_index"`
+	Ledger       struct {
+		CloseTime int64 `json:"close_time"`
+	} `json:"ledger"`
+}
+
+// LatestHeight returns the index of the latest validated ledger.
+func (f *XRPLFinder) LatestHeight(ctx context.Context) (int64, error) {
+	ledger, err := f.ledger(ctx, "validated")
+	if err != nil {
+		return 0, fmt.Errorf("error getting validated ledger: %v", err)
+	}
+
+	return ledger.LedgerIndex, nil
+}
+
+// FinalizedHeight implements Finalizer. Validated ledgers are final, so this is the latest height.
+func (f *XRPLFinder) FinalizedHeight(ctx context.Context) (int64, error) {
+	return f.LatestHeight(ctx)
+}
+
+// BlockTimestamp returns the close time of the ledger with the given index.
+func (f *XRPLFinder) BlockTimestamp(ctx context.Context, index int64) (int64, error) {
+	ledger, err := f.ledger(ctx, index)
+	if err != nil {
+		return 0, fmt.Errorf("error getting ledger %d: %v", index, err)
+	}
+
+	return ledger.Ledger.CloseTime + rippleEpoch, nil
+}
+
+// ledger calls the ledger method for the ledger identified by index, a ledger index or a shortcut like validated.
+func (f *XRPLFinder) ledger(ctx context.Context, index interface{}) (*xrplLedgerResult, error) {
+	request := map[string]interface{}{
+		"method": "ledger",
+		"params": []interface{}{map[string]interface{}{"ledger_index": index}},
+	}
+
+	var response xrplResponse
+	if err := f.client.PostJSON(ctx, "", request, &response); err != nil {
+		return nil, err
+	}
+
+	var result xrplLedgerResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	// Servers without the full history answer lgrNotFound for the ledgers they lack
+	if result.Status != "success" {
+		return nil, fmt.Errorf("rpc error %s: %s", result.Error, result.ErrorMessage)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,221 @@
+// Package github commits files and opens pull requests through the GitHub REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the URL of the public GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+// ErrNotFound is returned for resources that don't exist, or that the token can't see.
+var ErrNotFound = errors.New("not found")
+
+// Client calls the GitHub REST API on behalf of the owner of a token.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client authenticating with token against the API at baseURL, DefaultBaseURL if empty.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), token: token, httpClient: http.DefaultClient}
+}
+
+// Repository identifies a repository by its owner and name, like RSS3-Network/node.
+type Repository struct {
+	Owner string
+	Name  string
+}
+
+// ParseRepository parses a repository given as owner/name.
+func ParseRepository(value string) (Repository, error) {
+	owner, name, ok := strings.Cut(value, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return Repository{}, fmt.Errorf("invalid repository %q: expected owner/name", value)
+	}
+
+	return Repository{Owner: owner, Name: name}, nil
+}
+
+func (r Repository) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// PullRequest is an opened pull request.
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Change is a file to commit to a new branch and propose in a pull request.
+type Change struct {
+	// Base is the branch to propose the change against, the default branch of the repository if empty.
+	Base string
+	// Branch is the new branch the change is committed to.
+	Branch  string
+	Path    string
+	Content []byte
+	// Message is the commit message, also used as the title of the pull request.
+	Message string
+	// Body is the description of the pull request, in Markdown.
+	Body string
+}
+
+// ProposeChange commits change to a new branch forked from its base and opens a pull request for it.
+// It returns nil without committing anything if the file on the base branch already has the content.
+func (c *Client) ProposeChange(ctx context.Context, repository Repository, change Change) (*PullRequest, error) {
+	base := change.Base
+	if base == "" {
+		var info struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := c.request(ctx, http.MethodGet, repository.path(""), nil, &info); err != nil {
+			return nil, fmt.Errorf("error getting repository %s: %w", repository, err)
+		}
+
+		base = info.DefaultBranch
+	}
+
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := c.request(ctx, http.MethodGet, repository.path("/git/ref/heads/"+url.PathEscape(base)), nil, &ref); err != nil {
+		return nil, fmt.Errorf("error getting branch %s: %w", base, err)
+	}
+
+	var file struct {
+		SHA     string `json:"sha"`
+		Content string `json:"content"`
+	}
+	err := c.request(ctx, http.MethodGet, repository.path("/contents/"+escapePath(change.Path)+"?ref="+url.QueryEscape(base)), nil, &file)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("error getting %s on %s: %w", change.Path, base, err)
+	}
+
+	if err == nil {
+		// The API wraps the base64 content in lines
+		existing, decodeErr := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+		if decodeErr == nil && bytes.Equal(existing, change.Content) {
+			return nil, nil
+		}
+	}
+
+	createRef := map[string]string{"ref": "refs/heads/" + change.Branch, "sha": ref.Object.SHA}
+	if err := c.request(ctx, http.MethodPost, repository.path("/git/refs"), createRef, nil); err != nil {
+		return nil, fmt.Errorf("error creating branch %s: %w", change.Branch, err)
+	}
+
+	commit := map[string]string{
+		"message": change.Message,
+		"content": base64.StdEncoding.EncodeToString(change.Content),
+		"branch":  change.Branch,
+	}
+	if file.SHA != "" {
+		commit["sha"] = file.SHA
+	}
+
+	if err := c.request(ctx, http.MethodPut, repository.path("/contents/"+escapePath(change.Path)), commit, nil); err != nil {
+		return nil, fmt.Errorf("error committing %s: %w", change.Path, err)
+	}
+
+	pullRequest := map[string]string{"title": change.Message, "head": change.Branch, "base": base, "body": change.Body}
+
+	var opened PullRequest
+	if err := c.request(ctx, http.MethodPost, repository.path("/pulls"), pullRequest, &opened); err != nil {
+		return nil, fmt.Errorf("error opening pull request: %w", err)
+	}
+
+	return &opened, nil
+}
+
+// path returns the API path of the repository followed by suffix.
+func (r Repository) path(suffix string) string {
+	return "/repos/" + url.PathEscape(r.Owner) + "/" + url.PathEscape(r.Name) + suffix
+}
+
+// escapePath escapes every segment of a file path in a repository.
+func escapePath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for index, segment := range segments {
+		segments[index] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// request sends a request to the API with an optional JSON body and decodes the JSON response into result, if not nil.
+func (c *Client) request(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request body: %w", err)
+		}
+
+		reader = bytes.NewReader(content)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+c.token)
+	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		if json.Unmarshal(content, &apiError) != nil || apiError.Message == "" {
+			apiError.Message = string(content)
+		}
+
+		return fmt.Errorf("unexpected status code %d: %s", response.StatusCode, apiError.Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package history records the resolutions of every run in a SQL database, PostgreSQL or SQLite, so that the way
+// start blocks evolved across epochs can be queried and past param sets audited.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// Drivers of the supported databases
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Run is a resolve run, with the resolution of every network.
+type Run struct {
+	Target      int64
+	Direction   string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Resolutions []Resolution
+}
+
+// Resolution is the outcome of resolving a network in a run.
+type Resolution struct {
+	Network string
+	// Block, BlockTimestamp and Difference are only set for networks that were resolved.
+	Block          *int64
+	BlockTimestamp *int64
+	// Target is the timestamp the network was resolved for, which differs from the target of the run for
+	// networks started at their genesis.
+	Target int64
+	// Difference is the number of seconds between the block and the target, negative for blocks before it.
+	Difference *int64
+	// Endpoints are the endpoints that answered the calls of the network.
+	Endpoints  []string
+	RPCCalls   int
+	DurationMS int64
+	Error      string
+}
+
+// schema creates the tables of the history. %s is the type of the auto-incremented keys of the database.
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id %s PRIMARY KEY,
+	target BIGINT NOT NULL,
+	direction TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	finished_at TIMESTAMP NOT NULL
+);
+CREATE TABLE IF NOT EXISTS resolutions (
+	run_id BIGINT NOT NULL REFERENCES runs (id),
+	network TEXT NOT NULL,
+	target BIGINT NOT NULL,
+	block BIGINT,
+	block_timestamp BIGINT,
+	difference BIGINT,
+	endpoints TEXT NOT NULL,
+	rpc_calls INTEGER NOT NULL,
+	duration_ms BIGINT NOT NULL,
+	error TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS resolutions_network ON resolutions (network, run_id);
+`
+
+// DB is the database the history is kept in.
+type DB struct {
+	db *sql.DB
+	// postgres is set for PostgreSQL, whose placeholders are numbered.
+	postgres bool
+}
+
+// Open opens the database at dsn, a postgres:// URL or the path of an SQLite file, optionally prefixed with
+// sqlite://, and creates the tables of the history if missing.
+func Open(ctx context.Context, dsn string) (*DB, error) {
+	driver, source, keyType := "sqlite3", strings.TrimPrefix(dsn, "sqlite://"), "INTEGER"
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		driver, source, keyType = "postgres", dsn, "BIGSERIAL"
+	}
+
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		return nil, fmt.Errorf("error opening history database: %w", err)
+	}
+
+	// SQLite runs a statement at a time, so the schema is created statement by statement
+	for _, statement := range strings.Split(fmt.Sprintf(schema, keyType), ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			db.Close()
+
+			return nil, fmt.Errorf("error creating history tables: %w", err)
+		}
+	}
+
+	return &DB{db: db, postgres: driver == "postgres"}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Record appends run to the history, along with its resolutions, in a single transaction.
+func (d *DB) Record(ctx context.Context, run Run) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error recording run: %w", err)
+	}
+	// Rolling back a committed transaction does nothing
+	defer tx.Rollback()
+
+	var runID int64
+	if err := tx.QueryRowContext(ctx, d.rebind("INSERT INTO runs (target, direction, started_at, finished_at) VALUES (?, ?, ?, ?) RETURNING id"),
+		run.Target, run.Direction, run.StartedAt.UTC(), run.FinishedAt.UTC()).Scan(&runID); err != nil {
+		return fmt.Errorf("error recording run: %w", err)
+	}
+
+	insert, err := tx.PrepareContext(ctx, d.rebind(`INSERT INTO resolutions
+		(run_id, network, target, block, block_timestamp, difference, endpoints, rpc_calls, duration_ms, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`))
+	if err != nil {
+		return fmt.Errorf("error recording resolutions: %w", err)
+	}
+	defer insert.Close()
+
+	for _, resolution := range run.Resolutions {
+		if _, err := insert.ExecContext(ctx, runID, resolution.Network, resolution.Target, resolution.Block, resolution.BlockTimestamp,
+			resolution.Difference, strings.Join(resolution.Endpoints, ","), resolution.RPCCalls, resolution.DurationMS, resolution.Error); err != nil {
+			return fmt.Errorf("error recording resolution of %s: %w", resolution.Network, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error recording run: %w", err)
+	}
+
+	return nil
+}
+
+// rebind numbers the ? placeholders of query for PostgreSQL.
+func (d *DB) rebind(query string) string {
+	if !d.postgres {
+		return query
+	}
+
+	var builder strings.Builder
+
+	placeholder := 0
+	for _, r := range query {
+		if r != '?' {
+			builder.WriteRune(r)
+			continue
+		}
+
+		placeholder++
+		fmt.Fprintf(&builder, "$%d", placeholder)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,89 @@
+// Package ipfs adds and pins files through the HTTP RPC API of an IPFS node, like Kubo.
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIURL is the URL of the RPC API of a local IPFS node.
+const DefaultAPIURL = "http://127.0.0.1:5001"
+
+// Client calls the RPC API of an IPFS node.
+type Client struct {
+	apiURL     string
+	httpClient *http.Client
+	// authorization is sent as the Authorization header, for hosted nodes behind authentication.
+	authorization string
+}
+
+// NewClient creates a Client for the RPC API at apiURL, DefaultAPIURL if empty. A non-empty authorization
+// is sent as the Authorization header of every request.
+func NewClient(apiURL, authorization string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &Client{apiURL: strings.TrimSuffix(apiURL, "/"), httpClient: http.DefaultClient, authorization: authorization}
+}
+
+// Add adds content as a file called name to the node, pins it, and returns its CID (version 1).
+func (c *Client) Add(ctx context.Context, name string, content []byte) (string, error) {
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return "", fmt.Errorf("error creating request body: %w", err)
+	}
+
+	if _, err := part.Write(content); err != nil {
+		return "", fmt.Errorf("error creating request body: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("error creating request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/api/v0/add?pin=true&cid-version=1", &body)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.authorization != "" {
+		request.Header.Set("Authorization", c.authorization)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error adding file: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+		return "", fmt.Errorf("error adding file: unexpected status code %d: %s", response.StatusCode, message)
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("error decoding added file: %w", err)
+	}
+
+	if added.Hash == "" {
+		return "", fmt.Errorf("error adding file: no CID in the answer")
+	}
+
+	return added.Hash, nil
+}
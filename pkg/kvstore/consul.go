@@ -0,0 +1,100 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Consul is the KV store of a Consul agent, reached through its HTTP API.
+type Consul struct {
+	// URL is the URL of the HTTP API of the agent, like http://127.0.0.1:8500.
+	URL string
+	// Token is the ACL token sent with requests, if ACLs are enabled.
+	Token      string
+	HTTPClient *http.Client
+}
+
+var _ Store = (*Consul)(nil)
+
+// Put implements Store.
+func (c *Consul) Put(ctx context.Context, key string, value []byte) error {
+	var stored bool
+	if err := c.do(ctx, http.MethodPut, key, nil, value, &stored); err != nil {
+		return fmt.Errorf("error putting %s: %w", key, err)
+	}
+
+	if !stored {
+		return fmt.Errorf("error putting %s: not stored", key)
+	}
+
+	return nil
+}
+
+// Keys implements Store.
+func (c *Consul) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	if err := c.do(ctx, http.MethodGet, prefix, url.Values{"keys": {""}}, nil, &keys); err != nil {
+		return nil, fmt.Errorf("error listing keys under %s: %w", prefix, err)
+	}
+
+	return keys, nil
+}
+
+// Delete implements Store.
+func (c *Consul) Delete(ctx context.Context, key string) error {
+	if err := c.do(ctx, http.MethodDelete, key, nil, nil, nil); err != nil {
+		return fmt.Errorf("error deleting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// do sends a request with body to the KV endpoint of key, decoding the answer into response if not nil. Missing
+// keys leave response as it is.
+func (c *Consul) do(ctx context.Context, method, key string, query url.Values, body []byte, response interface{}) error {
+	endpoint := strings.TrimSuffix(c.URL, "/") + "/v1/kv/" + (&url.URL{Path: key}).EscapedPath()
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	if c.Token != "" {
+		request.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	httpResponse, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return nil
+	}
+
+	if httpResponse.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(httpResponse.Body, 512))
+
+		return fmt.Errorf("unexpected status code %d: %s", httpResponse.StatusCode, message)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
+		return fmt.Errorf("error decoding answer: %w", err)
+	}
+
+	return nil
+}
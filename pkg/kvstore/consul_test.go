@@ -0,0 +1,134 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeConsul serves the KV endpoints of the HTTP API of a Consul agent, requiring token if set.
+type fakeConsul struct {
+	token string
+
+	locker sync.Mutex
+	values map[string][]byte
+}
+
+func newFakeConsul(t *testing.T, token string) (*fakeConsul, *httptest.Server) {
+	consul := fakeConsul{token: token, values: make(map[string][]byte)}
+
+	server := httptest.NewServer(http.HandlerFunc(consul.handle))
+	t.Cleanup(server.Close)
+
+	return &consul, server
+}
+
+func (f *fakeConsul) handle(w http.ResponseWriter, r *http.Request) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	if f.token != "" && r.Header.Get("X-Consul-Token") != f.token {
+		http.Error(w, "Permission denied: token with AccessorID '00000000-0000-0000-0000-000000000002' lacks permission 'key:write'", http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/v1/kv/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		value, _ := io.ReadAll(r.Body)
+		f.values[key] = value
+
+		_ = json.NewEncoder(w).Encode(true)
+	case http.MethodGet:
+		var keys []string
+		for stored := range f.values {
+			if strings.HasPrefix(stored, key) {
+				keys = append(keys, stored)
+			}
+		}
+
+		// Like Consul, missing keys answer 404
+		if len(keys) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(keys)
+	case http.MethodDelete:
+		delete(f.values, key)
+
+		_ = json.NewEncoder(w).Encode(true)
+	}
+}
+
+func TestConsul(t *testing.T) {
+	for _, token := range []string{"", "acl-token"} {
+		fake, server := newFakeConsul(t, token)
+
+		store := New(Location{Scheme: "consul", URL: server.URL}, Credentials{Token: token})
+		ctx := context.Background()
+
+		for _, key := range []string{"rss3/params/networks/ethereum", "rss3/params/networks/base", "rss3/paramsx"} {
+			if err := store.Put(ctx, key, []byte("20000000")); err != nil {
+				t.Fatalf("Put(%s) error = %v", key, err)
+			}
+		}
+
+		if value := string(fake.values["rss3/params/networks/ethereum"]); value != "20000000" {
+			t.Errorf("stored value = %q, want 20000000", value)
+		}
+
+		keys, err := store.Keys(ctx, "rss3/params/networks/")
+		if err != nil {
+			t.Fatalf("Keys() error = %v", err)
+		}
+
+		slices.Sort(keys)
+		if want := []string{"rss3/params/networks/base", "rss3/params/networks/ethereum"}; !slices.Equal(keys, want) {
+			t.Errorf("Keys() = %v, want %v", keys, want)
+		}
+
+		// Missing keys list as nothing and delete without error
+		if keys, err := store.Keys(ctx, "rss3/missing/"); err != nil || len(keys) != 0 {
+			t.Errorf("Keys() of a missing prefix = %v, %v, want no keys", keys, err)
+		}
+
+		if err := store.Delete(ctx, "rss3/missing"); err != nil {
+			t.Errorf("Delete() of a missing key error = %v", err)
+		}
+
+		if err := store.Delete(ctx, "rss3/params/networks/base"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		if _, ok := fake.values["rss3/params/networks/base"]; ok {
+			t.Errorf("Delete() left the key in the store")
+		}
+	}
+}
+
+func TestConsulAuthFailure(t *testing.T) {
+	_, server := newFakeConsul(t, "acl-token")
+
+	store := New(Location{Scheme: "consul", URL: server.URL}, Credentials{Token: "wrong-token"})
+
+	if err := store.Put(context.Background(), "rss3/params", []byte("{}")); err == nil {
+		t.Errorf("Put() error = nil, want a permission error")
+	}
+
+	// A denied listing isn't mistaken for missing keys
+	if _, err := store.Keys(context.Background(), "rss3/"); err == nil {
+		t.Errorf("Keys() error = nil, want a permission error")
+	}
+}
@@ -0,0 +1,166 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Etcd is an etcd cluster, reached through the JSON gateway of its v3 API.
+type Etcd struct {
+	// URL is the URL of a member of the cluster, like http://127.0.0.1:2379.
+	URL string
+	// Username and Password authenticate with clusters with authentication enabled, and are left empty otherwise.
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+
+	// token is the authentication token of the user, once authenticated.
+	token string
+}
+
+var _ Store = (*Etcd)(nil)
+
+// Put implements Store.
+func (e *Etcd) Put(ctx context.Context, key string, value []byte) error {
+	request := map[string]string{"key": encodeEtcd([]byte(key)), "value": encodeEtcd(value)}
+
+	if err := e.call(ctx, "kv/put", request, nil); err != nil {
+		return fmt.Errorf("error putting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Keys implements Store.
+func (e *Etcd) Keys(ctx context.Context, prefix string) ([]string, error) {
+	request := map[string]interface{}{
+		"key":       encodeEtcd([]byte(prefix)),
+		"range_end": encodeEtcd(prefixEnd([]byte(prefix))),
+		"keys_only": true,
+	}
+
+	var response struct {
+		KVs []struct {
+			Key string `json:"key"`
+		} `json:"kvs"`
+	}
+	if err := e.call(ctx, "kv/range", request, &response); err != nil {
+		return nil, fmt.Errorf("error listing keys under %s: %w", prefix, err)
+	}
+
+	keys := make([]string, 0, len(response.KVs))
+	for _, kv := range response.KVs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error listing keys under %s: invalid key %q", prefix, kv.Key)
+		}
+
+		keys = append(keys, string(key))
+	}
+
+	return keys, nil
+}
+
+// Delete implements Store.
+func (e *Etcd) Delete(ctx context.Context, key string) error {
+	request := map[string]string{"key": encodeEtcd([]byte(key))}
+
+	if err := e.call(ctx, "kv/deleterange", request, nil); err != nil {
+		return fmt.Errorf("error deleting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// call posts request to the method of the v3 API at path, decoding the answer into response if not nil.
+func (e *Etcd) call(ctx context.Context, path string, request, response interface{}) error {
+	if e.Username != "" && e.token == "" {
+		if err := e.authenticate(ctx); err != nil {
+			return err
+		}
+	}
+
+	return e.post(ctx, path, request, response)
+}
+
+// authenticate gets the token of the user, sent with every following request.
+func (e *Etcd) authenticate(ctx context.Context) error {
+	var response struct {
+		Token string `json:"token"`
+	}
+	if err := e.post(ctx, "auth/authenticate", map[string]string{"name": e.Username, "password": e.Password}, &response); err != nil {
+		return fmt.Errorf("error authenticating as %s: %w", e.Username, err)
+	}
+
+	if response.Token == "" {
+		return fmt.Errorf("error authenticating as %s: no token in the answer", e.Username)
+	}
+
+	e.token = response.Token
+
+	return nil
+}
+
+func (e *Etcd) post(ctx context.Context, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error encoding request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.URL, "/")+"/v3/"+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if e.token != "" {
+		httpRequest.Header.Set("Authorization", e.token)
+	}
+
+	httpResponse, err := e.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(httpResponse.Body, 512))
+
+		return fmt.Errorf("unexpected status code %d: %s", httpResponse.StatusCode, message)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
+		return fmt.Errorf("error decoding answer: %w", err)
+	}
+
+	return nil
+}
+
+// encodeEtcd encodes bytes as the JSON gateway expects them.
+func encodeEtcd(value []byte) string {
+	return base64.StdEncoding.EncodeToString(value)
+}
+
+// prefixEnd returns the end of the range of keys starting with prefix, all keys for an empty prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return []byte{0}
+}
@@ -0,0 +1,180 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeEtcd serves the parts of the JSON gateway of the etcd v3 API that Etcd calls, requiring the password of
+// user root if password is set.
+type fakeEtcd struct {
+	password string
+
+	locker sync.Mutex
+	values map[string][]byte
+}
+
+func newFakeEtcd(t *testing.T, password string) (*fakeEtcd, *httptest.Server) {
+	etcd := fakeEtcd{password: password, values: make(map[string][]byte)}
+
+	server := httptest.NewServer(http.HandlerFunc(etcd.handle))
+	t.Cleanup(server.Close)
+
+	return &etcd, server
+}
+
+func (f *fakeEtcd) handle(w http.ResponseWriter, r *http.Request) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	var request struct {
+		Key      []byte `json:"key"`
+		RangeEnd []byte `json:"range_end"`
+		Value    []byte `json:"value"`
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Path == "/v3/auth/authenticate" {
+		if request.Name != "root" || request.Password != f.password {
+			http.Error(w, `{"error":"etcdserver: authentication failed, invalid user ID or password","code":3}`, http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "token-of-root"})
+
+		return
+	}
+
+	if f.password != "" && r.Header.Get("Authorization") != "token-of-root" {
+		http.Error(w, `{"error":"etcdserver: user name is empty","code":3}`, http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v3/kv/put":
+		f.values[string(request.Key)] = request.Value
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{})
+	case "/v3/kv/range":
+		var kvs []map[string]string
+		for key := range f.values {
+			if bytes.Compare([]byte(key), request.Key) >= 0 && bytes.Compare([]byte(key), request.RangeEnd) < 0 {
+				kvs = append(kvs, map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))})
+			}
+		}
+
+		// Like the gateway, empty ranges leave out kvs
+		response := map[string]interface{}{}
+		if len(kvs) > 0 {
+			response["kvs"] = kvs
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	case "/v3/kv/deleterange":
+		delete(f.values, string(request.Key))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestEtcd(t *testing.T) {
+	for _, password := range []string{"", "secret"} {
+		fake, server := newFakeEtcd(t, password)
+
+		username := ""
+		if password != "" {
+			username = "root"
+		}
+
+		store := New(Location{Scheme: "etcd", URL: server.URL}, Credentials{Username: username, Password: password})
+		ctx := context.Background()
+
+		for _, key := range []string{"rss3/params/networks/ethereum", "rss3/params/networks/base", "rss3/paramsx"} {
+			if err := store.Put(ctx, key, []byte("20000000")); err != nil {
+				t.Fatalf("Put(%s) error = %v", key, err)
+			}
+		}
+
+		if value := string(fake.values["rss3/params/networks/ethereum"]); value != "20000000" {
+			t.Errorf("stored value = %q, want 20000000", value)
+		}
+
+		keys, err := store.Keys(ctx, "rss3/params/networks/")
+		if err != nil {
+			t.Fatalf("Keys() error = %v", err)
+		}
+
+		slices.Sort(keys)
+		if want := []string{"rss3/params/networks/base", "rss3/params/networks/ethereum"}; !slices.Equal(keys, want) {
+			t.Errorf("Keys() = %v, want %v", keys, want)
+		}
+
+		// Missing keys list as nothing and delete without error
+		if keys, err := store.Keys(ctx, "rss3/missing/"); err != nil || len(keys) != 0 {
+			t.Errorf("Keys() of a missing prefix = %v, %v, want no keys", keys, err)
+		}
+
+		if err := store.Delete(ctx, "rss3/missing"); err != nil {
+			t.Errorf("Delete() of a missing key error = %v", err)
+		}
+
+		if err := store.Delete(ctx, "rss3/params/networks/base"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		if _, ok := fake.values["rss3/params/networks/base"]; ok {
+			t.Errorf("Delete() left the key in the store")
+		}
+	}
+}
+
+func TestEtcdAuthFailure(t *testing.T) {
+	_, server := newFakeEtcd(t, "secret")
+
+	tests := []struct {
+		name        string
+		credentials Credentials
+	}{
+		{name: "wrong password", credentials: Credentials{Username: "root", Password: "wrong"}},
+		{name: "no credentials", credentials: Credentials{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := New(Location{Scheme: "etcd", URL: server.URL}, test.credentials)
+
+			if err := store.Put(context.Background(), "rss3/params", []byte("{}")); err == nil {
+				t.Errorf("Put() error = nil, want an authentication error")
+			}
+		})
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		prefix []byte
+		want   []byte
+	}{
+		{prefix: []byte("rss3/"), want: []byte("rss30")},
+		{prefix: []byte{'a', 0xff}, want: []byte{'b'}},
+		{prefix: nil, want: []byte{0}},
+	}
+
+	for _, test := range tests {
+		if got := prefixEnd(test.prefix); !bytes.Equal(got, test.want) {
+			t.Errorf("prefixEnd(%q) = %q, want %q", test.prefix, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package kvstore writes keys to the key-value stores fleets of nodes watch for changes, etcd and Consul, through
+// their HTTP APIs.
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Store is a key-value store.
+type Store interface {
+	// Put sets key to value.
+	Put(ctx context.Context, key string, value []byte) error
+	// Keys lists the keys starting with prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// Delete deletes key, succeeding if it doesn't exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// Location is a store and a key prefix, parsed from a URL like etcd://127.0.0.1:2379/rss3/params or
+// consul+https://consul:8501/rss3/params.
+type Location struct {
+	// Scheme is etcd or consul.
+	Scheme string
+	// URL is the URL of the HTTP API of the store.
+	URL    string
+	Prefix string
+}
+
+// ParseLocation parses an etcd:// or consul:// URL into the URL of the store and a key prefix. The API is reached
+// over HTTP, or HTTPS for the etcd+https and consul+https schemes.
+func ParseLocation(rawURL string) (Location, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return Location{}, fmt.Errorf("invalid location %q: expected etcd://host:port/prefix or consul://host:port/prefix", rawURL)
+	}
+
+	scheme, transport, _ := strings.Cut(parsed.Scheme, "+")
+	if transport == "" {
+		transport = "http"
+	}
+
+	if (scheme != "etcd" && scheme != "consul") || (transport != "http" && transport != "https") {
+		return Location{}, fmt.Errorf("invalid location %q: expected etcd://host:port/prefix or consul://host:port/prefix", rawURL)
+	}
+
+	return Location{Scheme: scheme, URL: transport + "://" + parsed.Host, Prefix: strings.Trim(parsed.Path, "/")}, nil
+}
+
+// Key returns the key of name under the prefix of l.
+func (l Location) Key(name string) string {
+	if l.Prefix == "" {
+		return name
+	}
+
+	return l.Prefix + "/" + name
+}
+
+// Credentials authenticate with a store. Etcd uses the username and password, Consul the token.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// New returns the store at location, authenticated with credentials.
+func New(location Location, credentials Credentials) Store {
+	if location.Scheme == "consul" {
+		return &Consul{URL: location.URL, Token: credentials.Token, HTTPClient: http.DefaultClient}
+	}
+
+	return &Etcd{URL: location.URL, Username: credentials.Username, Password: credentials.Password, HTTPClient: http.DefaultClient}
+}
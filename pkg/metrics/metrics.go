@@ -0,0 +1,252 @@
+// Package metrics keeps counters, gauges and histograms with labels and serves them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default upper bounds of histogram buckets, in seconds, suited to RPC and search durations.
+var DefBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Registry holds metrics and writes them in the Prometheus text format.
+type Registry struct {
+	locker  sync.Mutex
+	metrics []*metric
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+// metric is a metric family, with a series for every combination of label values.
+type metric struct {
+	name    string
+	help    string
+	kind    metricType
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series holds the value of a metric for one combination of label values.
+type series struct {
+	labelValues []string
+	value       float64
+	// bucketCounts and count are only used by histograms, whose value is the sum of observations.
+	bucketCounts []uint64
+	count        uint64
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	registry *Registry
+	metric   *metric
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	registry *Registry
+	metric   *metric
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	registry *Registry
+	metric   *metric
+}
+
+// NewCounterVec registers a counter called name, partitioned by labels.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{registry: r, metric: r.register(name, help, counterType, labels, nil)}
+}
+
+// NewGaugeVec registers a gauge called name, partitioned by labels.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{registry: r, metric: r.register(name, help, gaugeType, labels, nil)}
+}
+
+// NewHistogramVec registers a histogram called name with the given bucket upper bounds, partitioned by labels.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &HistogramVec{registry: r, metric: r.register(name, help, histogramType, labels, sorted)}
+}
+
+// Add increases the counter for labelValues by delta, which must not be negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s decreased", c.metric.name))
+	}
+
+	c.registry.update(c.metric, labelValues, func(s *series) {
+		s.value += delta
+	})
+}
+
+// Inc increases the counter for labelValues by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Set sets the gauge for labelValues to value.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.registry.update(g.metric, labelValues, func(s *series) {
+		s.value = value
+	})
+}
+
+// Observe adds value to the histogram for labelValues.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.registry.update(h.metric, labelValues, func(s *series) {
+		if s.bucketCounts == nil {
+			s.bucketCounts = make([]uint64, len(h.metric.buckets))
+		}
+
+		for index, bound := range h.metric.buckets {
+			if value <= bound {
+				s.bucketCounts[index]++
+			}
+		}
+
+		s.value += value
+		s.count++
+	})
+}
+
+// register adds a metric family to the registry. It panics if name is already registered.
+func (r *Registry) register(name, help string, kind metricType, labels []string, buckets []float64) *metric {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	for _, existing := range r.metrics {
+		if existing.name == name {
+			panic(fmt.Sprintf("metrics: %s registered twice", name))
+		}
+	}
+
+	registered := &metric{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*series)}
+	r.metrics = append(r.metrics, registered)
+
+	return registered
+}
+
+// update applies change to the series of m for labelValues, creating it if needed.
+func (r *Registry) update(m *metric, labelValues []string, change func(s *series)) {
+	if len(labelValues) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", m.name, len(m.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		m.series[key] = s
+	}
+
+	change(s)
+}
+
+// WriteTo writes every metric to w in the Prometheus text format, with series sorted by label values.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	var builder strings.Builder
+
+	for _, m := range r.metrics {
+		fmt.Fprintf(&builder, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind)
+
+		keys := make([]string, 0, len(m.series))
+		for key := range m.series {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := m.series[key]
+
+			if m.kind != histogramType {
+				fmt.Fprintf(&builder, "%s%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+				continue
+			}
+
+			for index, bound := range m.buckets {
+				fmt.Fprintf(&builder, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", formatValue(bound)), s.bucketCounts[index])
+			}
+
+			fmt.Fprintf(&builder, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", "+Inf"), s.count)
+			fmt.Fprintf(&builder, "%s_sum%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+			fmt.Fprintf(&builder, "%s_count%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), s.count)
+		}
+	}
+
+	written, err := io.WriteString(w, builder.String())
+
+	return int64(written), err
+}
+
+// Handler returns an HTTP handler serving the metrics of r to Prometheus scrapes.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// formatLabels formats labels and their values like {network="ethereum"}, followed by the extra label if any.
+func formatLabels(labels, values []string, extraLabel, extraValue string) string {
+	pairs := make([]string, 0, len(labels)+1)
+
+	for index, label := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", label, values[index]))
+	}
+
+	if extraLabel != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", extraLabel, extraValue))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats a sample value, spelling infinities the way Prometheus does.
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp escapes backslashes and line feeds in help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
@@ -0,0 +1,286 @@
+// Package mockchain serves synthetic EVM chains over JSON-RPC, whose blocks come at a steady pace from a genesis
+// time, so the tool can be run end to end without real endpoints and with answers known in advance.
+package mockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Chain describes a synthetic chain.
+type Chain struct {
+	ChainID int64 `json:"chain_id"`
+	// GenesisTime is the timestamp of block 0.
+	GenesisTime int64 `json:"genesis_time"`
+	// BlockTime is the number of seconds between blocks. Fractions make consecutive blocks share timestamps,
+	// like on chains producing several blocks a second.
+	BlockTime float64 `json:"block_time"`
+	// Height is the number of the latest block, which is fixed so that answers don't depend on the time.
+	Height int64 `json:"height"`
+	// Contracts maps the addresses of contracts to the block they were deployed in, which also holds
+	// their first log.
+	Contracts map[string]int64 `json:"contracts,omitempty"`
+}
+
+// DefaultChain is a chain with the genesis time of Ethereum and a block every 12 seconds, whose latest block
+// is decades ahead so that any past target is within it.
+var DefaultChain = Chain{ChainID: 1, GenesisTime: 1438269973, BlockTime: 12, Height: 100_000_000}
+
+// Finality lags of the finalized and safe block tags behind the latest block, as on Ethereum.
+const (
+	finalizedLag = 64
+	safeLag      = 32
+)
+
+// Timestamp returns the timestamp of the block at number.
+func (c Chain) Timestamp(number int64) int64 {
+	return c.GenesisTime + int64(math.Floor(float64(number)*c.BlockTime))
+}
+
+// Validate checks that c describes a chain that can be served.
+func (c Chain) Validate() error {
+	if c.BlockTime <= 0 {
+		return fmt.Errorf("invalid block time %v: must be positive", c.BlockTime)
+	}
+
+	if c.Height < 1 {
+		return fmt.Errorf("invalid height %d: must be at least 1", c.Height)
+	}
+
+	return nil
+}
+
+// Server serves a Chain over JSON-RPC on a local address.
+type Server struct {
+	chain  Chain
+	server *httptest.Server
+	calls  atomic.Int64
+}
+
+// NewServer starts serving chain on a local address until closed.
+func NewServer(chain Chain) *Server {
+	server := Server{chain: chain}
+	server.server = httptest.NewServer(http.HandlerFunc(server.handle))
+
+	return &server
+}
+
+// URL returns the URL of the JSON-RPC endpoint of s.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Calls returns the number of JSON-RPC calls served so far, counting every call of a batch.
+func (s *Server) Calls() int64 {
+	return s.calls.Load()
+}
+
+// Close stops serving.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// handle answers single and batched JSON-RPC requests.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var answer interface{}
+
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		var requests []request
+		if err := json.Unmarshal(body, &requests); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		responses := make([]response, 0, len(requests))
+		for _, request := range requests {
+			responses = append(responses, s.call(request))
+		}
+
+		answer = responses
+	} else {
+		var request request
+		if err := json.Unmarshal(body, &request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		answer = s.call(request)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(answer)
+}
+
+// call answers a single JSON-RPC request.
+func (s *Server) call(request request) response {
+	s.calls.Add(1)
+
+	result, err := s.result(request.Method, request.Params)
+	if err != nil {
+		return response{JSONRPC: "2.0", ID: request.ID, Error: &responseError{Code: -32602, Message: err.Error()}}
+	}
+
+	return response{JSONRPC: "2.0", ID: request.ID, Result: result}
+}
+
+// result returns the result of calling method with params.
+func (s *Server) result(method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "eth_chainId":
+		return hexutil.EncodeBig(big.NewInt(s.chain.ChainID)), nil
+	case "eth_blockNumber":
+		return hexutil.EncodeBig(big.NewInt(s.chain.Height)), nil
+	case "eth_getBlockByNumber":
+		number, err := s.blockNumber(params, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// Blocks past the latest one don't exist yet
+		if number > s.chain.Height {
+			return nil, nil
+		}
+
+		return map[string]string{
+			"number":    hexutil.EncodeBig(big.NewInt(number)),
+			"timestamp": hexutil.EncodeBig(big.NewInt(s.chain.Timestamp(number))),
+		}, nil
+	case "eth_getCode":
+		address, err := s.stringParam(params, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		number, err := s.blockNumber(params, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if deployed, ok := s.deployment(address); ok && number >= deployed {
+			return "0x00", nil
+		}
+
+		return "0x", nil
+	case "eth_getLogs":
+		return s.logs(params)
+	default:
+		return nil, fmt.Errorf("method %s is not supported by the mock chain", method)
+	}
+}
+
+// logs answers eth_getLogs with the first log of the contract filtered on, if it's in the range of blocks.
+func (s *Server) logs(params []json.RawMessage) (interface{}, error) {
+	var filter struct {
+		Address   string `json:"address"`
+		FromBlock string `json:"fromBlock"`
+		ToBlock   string `json:"toBlock"`
+	}
+	if len(params) == 0 || json.Unmarshal(params[0], &filter) != nil {
+		return nil, fmt.Errorf("invalid filter")
+	}
+
+	from, err := s.parseBlockNumber(filter.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.parseBlockNumber(filter.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []map[string]string{}
+	if deployed, ok := s.deployment(filter.Address); ok && from <= deployed && deployed <= to {
+		logs = append(logs, map[string]string{"address": filter.Address, "blockNumber": hexutil.EncodeBig(big.NewInt(deployed))})
+	}
+
+	return logs, nil
+}
+
+// deployment returns the block the contract at address was deployed in, if it's a contract of the chain.
+func (s *Server) deployment(address string) (int64, bool) {
+	for contract, deployed := range s.chain.Contracts {
+		if strings.EqualFold(contract, address) {
+			return deployed, true
+		}
+	}
+
+	return 0, false
+}
+
+// blockNumber returns the block number or tag at index of params as a block number.
+func (s *Server) blockNumber(params []json.RawMessage, index int) (int64, error) {
+	value, err := s.stringParam(params, index)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.parseBlockNumber(value)
+}
+
+// parseBlockNumber parses a block number or tag into a block number.
+func (s *Server) parseBlockNumber(value string) (int64, error) {
+	switch value {
+	case "", "latest", "pending":
+		return s.chain.Height, nil
+	case "earliest":
+		return 0, nil
+	case "finalized":
+		return max(s.chain.Height-finalizedLag, 0), nil
+	case "safe":
+		return max(s.chain.Height-safeLag, 0), nil
+	}
+
+	number, err := hexutil.DecodeBig(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block number %q: %v", value, err)
+	}
+
+	return number.Int64(), nil
+}
+
+// stringParam returns the string at index of params.
+func (s *Server) stringParam(params []json.RawMessage, index int) (string, error) {
+	if index >= len(params) {
+		return "", fmt.Errorf("missing parameter %d", index)
+	}
+
+	var value string
+	if err := json.Unmarshal(params[index], &value); err != nil {
+		return "", fmt.Errorf("invalid parameter %d: %v", index, err)
+	}
+
+	return value, nil
+}
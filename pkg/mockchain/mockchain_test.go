@@ -0,0 +1,155 @@
+package mockchain
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testChain has a contract deployed in block 500 and a block every 12 seconds.
+var testChain = Chain{
+	ChainID:     10,
+	GenesisTime: 1_600_000_000,
+	BlockTime:   12,
+	Height:      1000,
+	Contracts:   map[string]int64{"0xAbCdEf0000000000000000000000000000000001": 500},
+}
+
+// call posts body to server, decoding the answer into answer.
+func call(t *testing.T, server *Server, body string, answer interface{}) {
+	t.Helper()
+
+	response, err := http.Post(server.URL(), "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error calling the mock chain: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("mock chain answered status %d", response.StatusCode)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(answer); err != nil {
+		t.Fatalf("error decoding the answer of the mock chain: %v", err)
+	}
+}
+
+type testResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func TestTimestamp(t *testing.T) {
+	shared := Chain{GenesisTime: 1_600_000_000, BlockTime: 0.25, Height: 1000}
+
+	for number, want := range map[int64]int64{0: 1_600_000_000, 3: 1_600_000_000, 4: 1_600_000_001, 401: 1_600_000_100} {
+		if got := shared.Timestamp(number); got != want {
+			t.Errorf("Timestamp(%d) = %d, want %d", number, got, want)
+		}
+	}
+}
+
+func TestMethods(t *testing.T) {
+	server := NewServer(testChain)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		params string
+		want   string
+	}{
+		{name: "chain id", method: "eth_chainId", params: `[]`, want: `"0xa"`},
+		{name: "block number", method: "eth_blockNumber", params: `[]`, want: `"0x3e8"`},
+		{name: "block by number", method: "eth_getBlockByNumber", params: `["0x64", false]`, want: `{"number":"0x64","timestamp":"0x5f5e14b0"}`},
+		{name: "block past the head", method: "eth_getBlockByNumber", params: `["0x3e9", false]`, want: `null`},
+		{name: "latest block", method: "eth_getBlockByNumber", params: `["latest", false]`, want: `{"number":"0x3e8","timestamp":"0x5f5e3ee0"}`},
+		{name: "earliest block", method: "eth_getBlockByNumber", params: `["earliest", false]`, want: `{"number":"0x0","timestamp":"0x5f5e1000"}`},
+		{name: "finalized block", method: "eth_getBlockByNumber", params: `["finalized", false]`, want: `{"number":"0x3a8","timestamp":"0x5f5e3be0"}`},
+		{name: "safe block", method: "eth_getBlockByNumber", params: `["safe", false]`, want: `{"number":"0x3c8","timestamp":"0x5f5e3d60"}`},
+		{name: "code before deployment", method: "eth_getCode", params: `["0xabcdef0000000000000000000000000000000001", "0x1f3"]`, want: `"0x"`},
+		{name: "code at deployment", method: "eth_getCode", params: `["0xabcdef0000000000000000000000000000000001", "0x1f4"]`, want: `"0x00"`},
+		{name: "code of another address", method: "eth_getCode", params: `["0x0000000000000000000000000000000000000002", "latest"]`, want: `"0x"`},
+		{
+			name: "logs including deployment", method: "eth_getLogs",
+			params: `[{"address": "0xabcdef0000000000000000000000000000000001", "fromBlock": "0x1", "toBlock": "latest"}]`,
+			want:   `[{"address":"0xabcdef0000000000000000000000000000000001","blockNumber":"0x1f4"}]`,
+		},
+		{
+			name: "logs after deployment", method: "eth_getLogs",
+			params: `[{"address": "0xabcdef0000000000000000000000000000000001", "fromBlock": "0x1f5", "toBlock": "latest"}]`,
+			want:   `[]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var answer testResponse
+			call(t, server, `{"jsonrpc": "2.0", "id": 1, "method": "`+test.method+`", "params": `+test.params+`}`, &answer)
+
+			if answer.Error != nil {
+				t.Fatalf("%s error = %s", test.method, answer.Error.Message)
+			}
+
+			if string(answer.Result) != test.want {
+				t.Errorf("%s = %s, want %s", test.method, answer.Result, test.want)
+			}
+		})
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	server := NewServer(testChain)
+	defer server.Close()
+
+	var answer testResponse
+	call(t, server, `{"jsonrpc": "2.0", "id": 7, "method": "eth_sendRawTransaction", "params": ["0x00"]}`, &answer)
+
+	if answer.Error == nil {
+		t.Fatalf("eth_sendRawTransaction = %s, want an error", answer.Result)
+	}
+
+	if answer.ID != 7 {
+		t.Errorf("error id = %d, want 7", answer.ID)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server := NewServer(testChain)
+	defer server.Close()
+
+	var answers []testResponse
+	call(t, server, `[
+		{"jsonrpc": "2.0", "id": 1, "method": "eth_getBlockByNumber", "params": ["0x1", false]},
+		{"jsonrpc": "2.0", "id": 2, "method": "eth_unknown", "params": []},
+		{"jsonrpc": "2.0", "id": 3, "method": "eth_getBlockByNumber", "params": ["0x2", false]}
+	]`, &answers)
+
+	if len(answers) != 3 {
+		t.Fatalf("batch answered %d responses, want 3", len(answers))
+	}
+
+	for index, answer := range answers {
+		if answer.ID != index+1 {
+			t.Errorf("response %d has id %d, want %d", index, answer.ID, index+1)
+		}
+	}
+
+	if want := `{"number":"0x1","timestamp":"0x5f5e100c"}`; string(answers[0].Result) != want {
+		t.Errorf("first response = %s, want %s", answers[0].Result, want)
+	}
+
+	if answers[1].Error == nil {
+		t.Errorf("second response = %s, want an error", answers[1].Result)
+	}
+
+	if want := `{"number":"0x2","timestamp":"0x5f5e1018"}`; string(answers[2].Result) != want {
+		t.Errorf("third response = %s, want %s", answers[2].Result, want)
+	}
+
+	if calls := server.Calls(); calls != 3 {
+		t.Errorf("Calls() = %d, want 3", calls)
+	}
+}
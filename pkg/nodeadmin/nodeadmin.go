@@ -0,0 +1,78 @@
+// Package nodeadmin updates the network parameters of a running RSS3 Node through its admin API, so that they can
+// be rolled out without editing the config files of the node.
+package nodeadmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultPath is the path of the config endpoint of the admin API of a node.
+const DefaultPath = "/admin/config"
+
+// Params are the network parameters of a node, in the sections of its config.
+type Params struct {
+	NetworkStartBlock         map[string]int64   `json:"network_start_block"`
+	NetworkBlockTime          map[string]float64 `json:"network_block_time,omitempty"`
+	NetworkGenesisTimestamp   map[string]int64   `json:"network_genesis_timestamp,omitempty"`
+	NetworkFarcasterTimestamp map[string]int64   `json:"network_farcaster_timestamp,omitempty"`
+}
+
+// Client calls the admin API of a node.
+type Client struct {
+	configURL  string
+	httpClient *http.Client
+	// token is sent as a bearer token, which the admin API requires.
+	token string
+}
+
+// NewClient creates a Client for the node at nodeURL, whose config endpoint is at path, DefaultPath if empty.
+// A non-empty token is sent as a bearer token with every request.
+func NewClient(nodeURL, path, token string) *Client {
+	if path == "" {
+		path = DefaultPath
+	}
+
+	return &Client{
+		configURL:  strings.TrimSuffix(nodeURL, "/") + "/" + strings.TrimPrefix(path, "/"),
+		httpClient: http.DefaultClient,
+		token:      token,
+	}
+}
+
+// UpdateParams posts params to the config endpoint of the node, which applies them to the running node.
+func (c *Client) UpdateParams(ctx context.Context, params Params) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("error encoding params: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.configURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error updating params: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+		return fmt.Errorf("error updating params: unexpected status code %d: %s", response.StatusCode, message)
+	}
+
+	return nil
+}
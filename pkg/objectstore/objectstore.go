@@ -0,0 +1,116 @@
+// Package objectstore uploads objects to S3 and S3-compatible storage, like Google Cloud Storage through its
+// XML API, with the AWS SDK.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// gcsEndpoint is the endpoint of the XML API of Google Cloud Storage, which is compatible with S3.
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// defaultRegion is the region of buckets when none is configured.
+const defaultRegion = "us-east-1"
+
+// Credentials are the HMAC keys signing requests to a store.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials only.
+	SessionToken string
+}
+
+// Store is a bucket of an S3-compatible object store.
+type Store struct {
+	Bucket   string
+	uploader *manager.Uploader
+}
+
+// NewS3 returns the store of bucket on AWS S3, or on the S3-compatible endpoint in AWS_ENDPOINT_URL_S3 or
+// AWS_ENDPOINT_URL, with the region and credentials of the default AWS config: the environment, the shared config
+// files or the role of the instance.
+func NewS3(ctx context.Context, bucket string) (*Store, error) {
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithDefaultRegion(defaultRegion))
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	if _, err := awsConfig.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("no credentials for s3://%s: %w", bucket, err)
+	}
+
+	return newStore(awsConfig, bucket), nil
+}
+
+// NewGCS returns the store of bucket on Google Cloud Storage, signing requests with the HMAC keys of credentials.
+func NewGCS(bucket string, keys Credentials) *Store {
+	awsConfig := aws.Config{
+		Region:       "auto",
+		BaseEndpoint: aws.String(gcsEndpoint),
+		Credentials:  credentials.NewStaticCredentialsProvider(keys.AccessKeyID, keys.SecretAccessKey, keys.SessionToken),
+	}
+
+	return newStore(awsConfig, bucket)
+}
+
+// newStore returns the store of bucket reached with awsConfig. Buckets are virtual-hosted on AWS, and path-style
+// on other endpoints, which don't all serve buckets as subdomains.
+func newStore(awsConfig aws.Config, bucket string) *Store {
+	client := s3.NewFromConfig(awsConfig, func(options *s3.Options) {
+		options.UsePathStyle = options.BaseEndpoint != nil
+	})
+
+	return &Store{Bucket: bucket, uploader: manager.NewUploader(client)}
+}
+
+// Location is a bucket and a key prefix, parsed from a URL like s3://bucket/params.
+type Location struct {
+	// Scheme is s3 or gs.
+	Scheme string
+	Bucket string
+	Prefix string
+}
+
+// ParseLocation parses an s3:// or gs:// URL into a bucket and key prefix.
+func ParseLocation(rawURL string) (Location, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "s3" && parsed.Scheme != "gs") || parsed.Host == "" {
+		return Location{}, fmt.Errorf("invalid location %q: expected s3://bucket/prefix or gs://bucket/prefix", rawURL)
+	}
+
+	return Location{Scheme: parsed.Scheme, Bucket: parsed.Host, Prefix: strings.Trim(parsed.Path, "/")}, nil
+}
+
+// Key returns the key of name under the prefix of l.
+func (l Location) Key(name string) string {
+	if l.Prefix == "" {
+		return name
+	}
+
+	return l.Prefix + "/" + name
+}
+
+// Put uploads content under key, replacing any object already there.
+func (s *Store) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPut(t *testing.T) {
+	var method, path, contentType, authorization, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+
+		method, path, body = r.Method, r.URL.Path, string(content)
+		contentType, authorization = r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	// Only the environment configures the store, rather than the shared config files of the machine
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	store, err := NewS3(context.Background(), "params")
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+
+	if err := store.Put(context.Background(), "node/latest/config.yaml", []byte("network_start_block: {}\n"), "application/yaml"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// Buckets of endpoints other than AWS are addressed path-style
+	if method != http.MethodPut || path != "/params/node/latest/config.yaml" {
+		t.Errorf("Put() sent %s %s, want PUT /params/node/latest/config.yaml", method, path)
+	}
+
+	if contentType != "application/yaml" {
+		t.Errorf("Put() sent Content-Type %q, want application/yaml", contentType)
+	}
+
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(authorization, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("Put() sent Authorization %q, want a Signature Version 4 of eu-west-1", authorization)
+	}
+
+	if body != "network_start_block: {}\n" {
+		t.Errorf("Put() sent body %q", body)
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		url   string
+		want  Location
+		valid bool
+	}{
+		{url: "s3://params", want: Location{Scheme: "s3", Bucket: "params"}, valid: true},
+		{url: "gs://params/node/mainnet/", want: Location{Scheme: "gs", Bucket: "params", Prefix: "node/mainnet"}, valid: true},
+		{url: "https://params/node"},
+		{url: "s3:///node"},
+	}
+
+	for _, test := range tests {
+		got, err := ParseLocation(test.url)
+		if test.valid != (err == nil) {
+			t.Errorf("ParseLocation(%q) error = %v, want valid %t", test.url, err, test.valid)
+		}
+
+		if got != test.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", test.url, got, test.want)
+		}
+	}
+}
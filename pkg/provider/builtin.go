@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"fmt"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+)
+
+// evmOptions are the endpoint.Pool options of EVM chains. Pruned nodes answer null for old blocks, which fails over
+// to archive nodes. eth_getLogs ranges refused as too large are answers, which the search narrows down.
+var evmOptions = []endpoint.Option{
+	endpoint.WithVolatile("eth_blockNumber"),
+	endpoint.WithRequiredResults("eth_getBlockByNumber"),
+	endpoint.WithAnswerErrors(blockfinder.IsLogRangeTooLarge),
+}
+
+func init() {
+	// Operations reading the chain head are volatile: raced endpoints a block apart answer them differently
+	Register("ethereum", func(config Config) (Provider, Connection, error) {
+		pool, err := endpoint.New(config.Name, config.URLs, append(config.EndpointOptions(), evmOptions...)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error connecting: %v", err)
+		}
+
+		// Rollups that went through a regenesis keep the migrated history below their first real block
+		finder := blockfinder.NewChainFinder(blockfinder.NewEthereumFinder(pool), max(1, config.MinHeight))
+		finder.SetScanWindow(config.ScanWindow)
+
+		return finder, pool, nil
+	})
+	Register("vsl", func(config Config) (Provider, Connection, error) {
+		pool, err := endpoint.New(config.Name, config.URLs, append(config.EndpointOptions(), evmOptions...)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error connecting: %v", err)
+		}
+
+		settlement := config.EpochContract
+		if settlement == "" {
+			settlement = blockfinder.VSLSettlementAddress
+		}
+
+		return blockfinder.NewVSLFinder(pool, settlement), pool, nil
+	})
+	Register("solana", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewSolanaFinder(pool) },
+		endpoint.WithAnswerErrors(blockfinder.IsSolanaSlotSkipped), endpoint.WithVolatile("getSlot", "getFirstAvailableBlock")))
+	Register("cosmos", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewCosmosFinder(pool) },
+		endpoint.WithVolatile("GET status")))
+	Register("bitcoin", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewBitcoinFinder(pool) },
+		endpoint.WithVolatile("getblockcount")))
+	Register("esplora", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewEsploraFinder(pool) },
+		endpoint.WithVolatile("GET blocks/tip/height")))
+	Register("blockbook", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewBlockbookFinder(pool) },
+		endpoint.WithVolatile("GET api/v2")))
+	Register("insight", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewInsightFinder(pool) },
+		endpoint.WithVolatile("GET api/status?q=getInfo")))
+	// NEAR posts every call to the same path, so its answers can't be told apart from the status
+	Register("near", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewNearFinder(pool) },
+		endpoint.WithVolatile("POST ")))
+	Register("substrate", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewSubstrateFinder(pool) },
+		endpoint.WithVolatile("chain_getHeader", "chain_getFinalizedHead")))
+	Register("sidecar", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewSidecarFinder(pool) },
+		endpoint.WithVolatile("GET blocks/head/header")))
+	// Hubs prune their event logs independently, so their earliest events differ
+	Register("farcaster", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewFarcasterFinder(pool) },
+		endpoint.WithVolatile("GET v1/events")))
+	Register("beacon", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewBeaconFinder(pool) },
+		endpoint.WithVolatile("GET eth/v1/beacon/headers/head", "GET eth/v1/beacon/headers/finalized")))
+	Register("tron", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewTronFinder(pool) },
+		endpoint.WithVolatile("POST wallet/getnowblock")))
+	Register("starknet", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewStarknetFinder(pool) },
+		endpoint.WithVolatile("starknet_blockNumber"), endpoint.WithRequiredResults("starknet_getBlockWithTxHashes")))
+	Register("aptos", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewAptosFinder(pool) },
+		endpoint.WithVolatile("GET v1")))
+	Register("sui", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewSuiFinder(pool) },
+		endpoint.WithVolatile("sui_getLatestCheckpointSequenceNumber")))
+	Register("ton", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewTonFinder(pool) },
+		endpoint.WithVolatile("GET api/v2/getMasterchainInfo")))
+	Register("filecoin", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewFilecoinFinder(pool) },
+		endpoint.WithVolatile("Filecoin.ChainHead")))
+	Register("algorand", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewAlgorandFinder(pool) },
+		endpoint.WithVolatile("GET v2/status")))
+	Register("stellar", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewStellarFinder(pool) },
+		endpoint.WithVolatile("GET ")))
+	// rippled posts every call to the same path, so its answers can't be told apart from the latest ledger
+	Register("xrpl", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewXRPLFinder(pool) },
+		endpoint.WithVolatile("POST ")))
+	Register("hedera", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewHederaFinder(pool) },
+		endpoint.WithVolatile("GET api/v1/blocks?order=desc&limit=1")))
+	Register("arweave", dialArweave)
+	// Explorers serve the chains they index, for cross-checking endpoints or when no endpoint is available
+	Register("etherscan", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewEtherscanFinder(pool) },
+		endpoint.WithVolatile("GET ?module=proxy&action=eth_blockNumber")))
+	Register("blockscout", poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewBlockscoutFinder(pool) },
+		endpoint.WithVolatile("GET api/v2/blocks?type=block")))
+}
+
+// poolProvider returns a Factory connecting to the network through an endpoint.Pool with the given extra options,
+// which newProvider queries.
+func poolProvider(newProvider func(pool *endpoint.Pool) Provider, options ...endpoint.Option) Factory {
+	return func(config Config) (Provider, Connection, error) {
+		pool, err := endpoint.New(config.Name, config.URLs, append(config.EndpointOptions(), options...)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error connecting: %v", err)
+		}
+
+		return newProvider(pool), pool, nil
+	}
+}
+
+// dialArweave connects to Arweave through the gateways of config. Gateways are probed up front, and ones
+// answering with errors such as 429 or 5xx are rotated to the back for the following calls.
+func dialArweave(config Config) (Provider, Connection, error) {
+	return poolProvider(func(pool *endpoint.Pool) Provider { return blockfinder.NewArweaveFinder(pool) },
+		endpoint.WithProbe("info"), endpoint.WithVolatile("GET info"))(config)
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+)
+
+// LookupFactory connects to an indexer service looking blocks up by timestamp.
+type LookupFactory func(config Config) (blockfinder.BlockLookup, Connection, error)
+
+var (
+	lookupsMutex sync.RWMutex
+	lookups      = make(map[string]LookupFactory)
+)
+
+// RegisterLookup makes a block lookup backend available under lookupType. It panics if lookupType is already
+// registered.
+func RegisterLookup(lookupType string, factory LookupFactory) {
+	lookupsMutex.Lock()
+	defer lookupsMutex.Unlock()
+
+	if _, ok := lookups[lookupType]; ok {
+		panic(fmt.Sprintf("provider: lookup type %q registered twice", lookupType))
+	}
+
+	lookups[lookupType] = factory
+}
+
+// LookupTypes returns the registered block lookup backends, sorted.
+func LookupTypes() []string {
+	lookupsMutex.RLock()
+	defer lookupsMutex.RUnlock()
+
+	types := make([]string, 0, len(lookups))
+	for lookupType := range lookups {
+		types = append(types, lookupType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// DialLookup connects to the block lookup backend of type lookupType, along with its connection to be closed
+// once done.
+func DialLookup(lookupType string, config Config) (blockfinder.BlockLookup, Connection, error) {
+	lookupsMutex.RLock()
+	factory, ok := lookups[lookupType]
+	lookupsMutex.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported lookup type %q", lookupType)
+	}
+
+	if config.Limiter == nil {
+		config.Limiter = rate.NewLimiter(rate.Inf, 1)
+	}
+
+	return factory(config)
+}
+
+// poolLookup returns a LookupFactory connecting to the backend through an endpoint.Pool, which newLookup queries.
+func poolLookup(newLookup func(pool *endpoint.Pool) blockfinder.BlockLookup) LookupFactory {
+	return func(config Config) (blockfinder.BlockLookup, Connection, error) {
+		pool, err := endpoint.New(config.Name, config.URLs, config.EndpointOptions()...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error connecting: %v", err)
+		}
+
+		return newLookup(pool), pool, nil
+	}
+}
+
+func init() {
+	RegisterLookup("etherscan", poolLookup(func(pool *endpoint.Pool) blockfinder.BlockLookup { return blockfinder.NewEtherscanFinder(pool) }))
+	RegisterLookup("moralis", poolLookup(func(pool *endpoint.Pool) blockfinder.BlockLookup { return blockfinder.NewMoralisLookup(pool) }))
+	RegisterLookup("covalent", poolLookup(func(pool *endpoint.Pool) blockfinder.BlockLookup { return blockfinder.NewCovalentLookup(pool) }))
+	RegisterLookup("subgraph", poolLookup(func(pool *endpoint.Pool) blockfinder.BlockLookup { return blockfinder.NewSubgraphLookup(pool) }))
+}
@@ -0,0 +1,170 @@
+// Package provider connects to networks by the type of their chain family, through a registry of providers.
+//
+// A chain family is added by implementing Provider and registering a Factory for its network type:
+//
+//	provider.Register("ethereum", func(config provider.Config) (provider.Provider, provider.Connection, error) {
+//		...
+//	})
+//
+// Providers that only implement Provider are searched by blockfinder.ChainFinder from height 1,
+// or Config.MinHeight, on.
+// Providers with a faster or more particular search also implement blockfinder.Finder.
+//
+// Indexer services looking blocks up by timestamp in a single call are registered apart with RegisterLookup,
+// as they only hint at blocks that are then confirmed on the chain.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+	"get-node-start-block/pkg/retry"
+)
+
+// Provider reports the head of a chain and the timestamp of its blocks. Heights may be slots or other
+// block-like sequence numbers, as long as their timestamps increase with them.
+type Provider interface {
+	// LatestHeight returns the height of the chain's head.
+	LatestHeight(ctx context.Context) (int64, error)
+	// BlockTimestamp returns the Unix timestamp of the block at height.
+	BlockTimestamp(ctx context.Context, height int64) (int64, error)
+}
+
+// Source is a Provider that can also find blocks by timestamp. Dial returns every provider as a Source.
+type Source interface {
+	Provider
+	blockfinder.Finder
+}
+
+// Connection is what a provider uses to reach its network.
+type Connection interface {
+	// Calls returns the number of requests sent to the network so far.
+	Calls() int
+	Close()
+}
+
+// Config is what a Factory needs to connect to a network.
+type Config struct {
+	// Name is the name of the network, used in logs.
+	Name string
+	URLs []string
+	// Retry is the policy for retrying failed requests.
+	Retry retry.Policy
+	// Limiter rate limits the requests to the network.
+	Limiter *rate.Limiter
+	// CallTimeout is the maximum time a single request to an endpoint may take, or 0 for no limit.
+	CallTimeout time.Duration
+	// EpochContract is the address of the contract tracking the epochs of the network, for the types aligning
+	// start blocks to epochs. Empty uses the network type's default.
+	EpochContract string
+	// Racing is the number of endpoints every request is sent to at once, or 0 or 1 to try them one by one.
+	Racing int
+	// MinHeight is the lowest height to search from, for chains whose early blocks can't be relied on,
+	// such as the history migrated into a rollup at a regenesis. 0 searches the whole chain.
+	MinHeight int64
+	// ScanWindow is the number of blocks on both sides of the block found by a search that are checked for chains
+	// whose timestamps aren't monotonic, like Arbitrum. 0 trusts the search.
+	ScanWindow int64
+	// Headers are added to every request to the network, like the API key of a gateway.
+	Headers http.Header
+	// Proxy is the proxy requests to the network go through, or nil for the proxy of the environment, if any.
+	Proxy *url.URL
+	// ObserveResponse is passed the response to every HTTP request to the network, if set.
+	ObserveResponse func(response *http.Response)
+}
+
+// EndpointOptions returns the endpoint.Pool options that apply the retry policy, rate limiter, call timeout,
+// racing, headers, proxy and response observer of c.
+func (c Config) EndpointOptions() []endpoint.Option {
+	options := []endpoint.Option{
+		endpoint.WithRetry(c.Retry),
+		endpoint.WithRateLimiter(c.Limiter),
+		endpoint.WithCallTimeout(c.CallTimeout),
+		endpoint.WithRacing(c.Racing),
+		endpoint.WithHeaders(c.Headers),
+	}
+
+	if c.Proxy != nil {
+		options = append(options, endpoint.WithProxy(c.Proxy))
+	}
+
+	if c.ObserveResponse != nil {
+		options = append(options, endpoint.WithResponseObserver(c.ObserveResponse))
+	}
+
+	return options
+}
+
+// Factory connects to a network of a chain family.
+type Factory func(config Config) (Provider, Connection, error)
+
+var (
+	factoriesMutex sync.RWMutex
+	factories      = make(map[string]Factory)
+)
+
+// Register makes a chain family available under networkType. It panics if networkType is already registered.
+func Register(networkType string, factory Factory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	if _, ok := factories[networkType]; ok {
+		panic(fmt.Sprintf("provider: network type %q registered twice", networkType))
+	}
+
+	factories[networkType] = factory
+}
+
+// Types returns the registered network types, sorted.
+func Types() []string {
+	factoriesMutex.RLock()
+	defer factoriesMutex.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for networkType := range factories {
+		types = append(types, networkType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// Dial connects to a network of type networkType and returns a Source for it,
+// along with its connection to be closed once done.
+func Dial(networkType string, config Config) (Source, Connection, error) {
+	factoriesMutex.RLock()
+	factory, ok := factories[networkType]
+	factoriesMutex.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported network type %q", networkType)
+	}
+
+	if config.Limiter == nil {
+		config.Limiter = rate.NewLimiter(rate.Inf, 1)
+	}
+
+	provider, connection, err := factory(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if source, ok := provider.(Source); ok {
+		return source, connection, nil
+	}
+
+	finder := blockfinder.NewChainFinder(provider, max(1, config.MinHeight))
+	finder.SetScanWindow(config.ScanWindow)
+
+	return finder, connection, nil
+}
@@ -0,0 +1,203 @@
+// Package rediscache caches block timestamps and resolved blocks in Redis, shared by every replica of a service so
+// that concurrent callers don't search the same blocks again.
+package rediscache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"get-node-start-block/pkg/blockfinder"
+	"get-node-start-block/pkg/endpoint"
+)
+
+// commandTimeout bounds the time spent on a single command, as the cache merely saves requests.
+const commandTimeout = 2 * time.Second
+
+// keyPrefix is the prefix of every key of the cache.
+const keyPrefix = "get-node-start-block:"
+
+// unlockScript deletes a lock only if it is still held by the caller, as it may have expired and been taken since.
+var unlockScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// Cache keeps block timestamps and resolved blocks in Redis, expiring them after a TTL so that blocks reorganized
+// away near the head of a chain don't stay cached.
+type Cache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// Open returns the cache on the Redis server at rawURL, like redis://:password@127.0.0.1:6379/0, or rediss:// for
+// TLS, keeping entries for ttl. The connection is checked with a PING.
+func Open(ctx context.Context, rawURL string, ttl time.Duration) (*Cache, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		// Parse errors quote the URL, password included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return nil, fmt.Errorf("invalid Redis URL %s: %w", endpoint.Redact(rawURL), err)
+	}
+
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return &Cache{client: client, ttl: ttl}, nil
+}
+
+// Close closes the connections to Redis.
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
+// Network returns the cache of the block timestamps of the named network.
+func (c *Cache) Network(name string) *NetworkCache {
+	return &NetworkCache{cache: c, name: name}
+}
+
+// Block returns the block of network resolved for timestamp with direction, if cached.
+func (c *Cache) Block(ctx context.Context, network, direction string, timestamp int64) (blockfinder.BlockRef, bool) {
+	value, ok := c.get(ctx, blockKey(network, direction, timestamp))
+	if !ok {
+		return blockfinder.BlockRef{}, false
+	}
+
+	number, blockTimestamp, found := strings.Cut(value, ":")
+
+	block := blockfinder.BlockRef{}
+
+	var numberErr, timestampErr error
+	block.Number, numberErr = strconv.ParseInt(number, 10, 64)
+	block.Timestamp, timestampErr = strconv.ParseInt(blockTimestamp, 10, 64)
+
+	if !found || numberErr != nil || timestampErr != nil {
+		slog.Warn("Invalid block in Redis cache", "network", network, "timestamp", timestamp, "value", value)
+		return blockfinder.BlockRef{}, false
+	}
+
+	return block, true
+}
+
+// SetBlock caches block as the block of network resolved for timestamp with direction.
+func (c *Cache) SetBlock(ctx context.Context, network, direction string, timestamp int64, block blockfinder.BlockRef) {
+	c.set(ctx, blockKey(network, direction, timestamp), fmt.Sprintf("%d:%d", block.Number, block.Timestamp))
+}
+
+// Lock takes the lock of resolving network for timestamp with direction, for ttl, so that a single replica
+// resolves it while the others wait for its block. It returns a function releasing the lock if it was taken,
+// and false if another caller holds it.
+func (c *Cache) Lock(ctx context.Context, network, direction string, timestamp int64, ttl time.Duration) (func(), bool) {
+	token := make([]byte, 16)
+	_, _ = rand.Read(token)
+
+	key := keyPrefix + "lock:" + network + ":" + direction + ":" + strconv.FormatInt(timestamp, 10)
+	value := hex.EncodeToString(token)
+
+	commandCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	// Failing to take the lock for another reason than it being held is no reason to wait
+	taken, err := c.client.SetNX(commandCtx, key, value, ttl).Result()
+	if err == nil && !taken {
+		return nil, false
+	}
+
+	if err != nil {
+		slog.Warn("Error locking in Redis cache", "network", network, "timestamp", timestamp, "error", err)
+		return func() {}, true
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		if err := unlockScript.Run(ctx, c.client, []string{key}, value).Err(); err != nil {
+			slog.Warn("Error unlocking in Redis cache", "network", network, "timestamp", timestamp, "error", err)
+		}
+	}, true
+}
+
+// get returns the value of key, if set. Failures are only logged, as the cache merely saves requests.
+func (c *Cache) get(ctx context.Context, key string) (string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false
+	}
+
+	if err != nil {
+		slog.Warn("Error reading from Redis cache", "key", key, "error", err)
+		return "", false
+	}
+
+	return value, true
+}
+
+// set sets key to value, expiring after the TTL of the cache. Failures are only logged.
+func (c *Cache) set(ctx context.Context, key, value string) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, key, value, c.ttl).Err(); err != nil {
+		slog.Warn("Error writing to Redis cache", "key", key, "error", err)
+	}
+}
+
+// blockKey is the key of the block of network resolved for timestamp with direction.
+func blockKey(network, direction string, timestamp int64) string {
+	return keyPrefix + "block:" + network + ":" + direction + ":" + strconv.FormatInt(timestamp, 10)
+}
+
+// NetworkCache is the part of a Cache holding the block timestamps of a single network.
+type NetworkCache struct {
+	cache *Cache
+	name  string
+}
+
+var _ blockfinder.TimestampCache = (*NetworkCache)(nil)
+
+// Timestamp returns the cached timestamp of the block with the given number, if any.
+func (c *NetworkCache) Timestamp(number int64) (int64, bool) {
+	value, ok := c.cache.get(context.Background(), c.key(number))
+	if !ok {
+		return 0, false
+	}
+
+	timestamp, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		slog.Warn("Invalid block timestamp in Redis cache", "network", c.name, "block", number, "value", value)
+		return 0, false
+	}
+
+	return timestamp, true
+}
+
+// SetTimestamp caches the timestamp of the block with the given number.
+func (c *NetworkCache) SetTimestamp(number, timestamp int64) {
+	c.cache.set(context.Background(), c.key(number), strconv.FormatInt(timestamp, 10))
+}
+
+func (c *NetworkCache) key(number int64) string {
+	return keyPrefix + "timestamp:" + c.name + ":" + strconv.FormatInt(number, 10)
+}
@@ -0,0 +1,102 @@
+// Package retry retries operations that fail transiently, waiting with exponential backoff and jitter in between.
+package retry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// Policy controls how often and how patiently an operation is retried.
+type Policy struct {
+	// Attempts is the maximum number of times the operation runs, including the first one.
+	Attempts int
+	// BaseDelay is the delay before the first retry, doubled before each following one.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy makes three attempts, half a second and then a second apart, give or take the jitter.
+var DefaultPolicy = Policy{
+	Attempts:  3,
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  10 * time.Second,
+}
+
+// NoRetry runs operations once.
+var NoRetry = Policy{Attempts: 1}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying. Do returns err itself, without the mark.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// Do runs operation until it succeeds, returns a Permanent error, ctx is done or policy runs out of attempts,
+// and returns the last error. description names the operation in the log.
+func Do(ctx context.Context, policy Policy, description string, operation func() error) error {
+	attempts := max(policy.Attempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay := policy.delay(attempt)
+
+		slog.Warn("Retrying after failed attempt", "operation", description, "attempt", attempt, "attempts", attempts, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns how long to wait after the given failed attempt. Half of the backoff is fixed and the other half
+// random, so that the networks resolved in parallel don't hammer a shared provider in lockstep.
+func (p Policy) delay(attempt int) time.Duration {
+	backoff := p.BaseDelay << min(attempt-1, 30)
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
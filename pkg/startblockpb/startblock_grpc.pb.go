@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: startblock/v1/startblock.proto
+
+package startblockpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StartBlockService_ResolveBlock_FullMethodName = "/startblock.v1.StartBlockService/ResolveBlock"
+	StartBlockService_ResolveAll_FullMethodName   = "/startblock.v1.StartBlockService/ResolveAll"
+)
+
+// StartBlockServiceClient is the client API for StartBlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StartBlockService resolves the blocks of the networks of the config for timestamps, for the RSS3 Node to call.
+//
+// Regenerate the Go code in pkg/startblockpb with protoc-gen-go and protoc-gen-go-grpc after changing this file:
+//
+//	protoc --proto_path=proto --go_out=. --go_opt=module=get-node-start-block \
+//	  --go-grpc_out=. --go-grpc_opt=module=get-node-start-block proto/startblock/v1/startblock.proto
+type StartBlockServiceClient interface {
+	// ResolveBlock finds the block of a network at a timestamp.
+	ResolveBlock(ctx context.Context, in *ResolveBlockRequest, opts ...grpc.CallOption) (*BlockRef, error)
+	// ResolveAll finds the start block of every network at a timestamp.
+	ResolveAll(ctx context.Context, in *ResolveAllRequest, opts ...grpc.CallOption) (*Params, error)
+}
+
+type startBlockServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStartBlockServiceClient(cc grpc.ClientConnInterface) StartBlockServiceClient {
+	return &startBlockServiceClient{cc}
+}
+
+func (c *startBlockServiceClient) ResolveBlock(ctx context.Context, in *ResolveBlockRequest, opts ...grpc.CallOption) (*BlockRef, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockRef)
+	err := c.cc.Invoke(ctx, StartBlockService_ResolveBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *startBlockServiceClient) ResolveAll(ctx context.Context, in *ResolveAllRequest, opts ...grpc.CallOption) (*Params, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Params)
+	err := c.cc.Invoke(ctx, StartBlockService_ResolveAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartBlockServiceServer is the server API for StartBlockService service.
+// All implementations must embed UnimplementedStartBlockServiceServer
+// for forward compatibility.
+//
+// StartBlockService resolves the blocks of the networks of the config for timestamps, for the RSS3 Node to call.
+//
+// Regenerate the Go code in pkg/startblockpb with protoc-gen-go and protoc-gen-go-grpc after changing this file:
+//
+//	protoc --proto_path=proto --go_out=. --go_opt=module=get-node-start-block \
+//	  --go-grpc_out=. --go-grpc_opt=module=get-node-start-block proto/startblock/v1/startblock.proto
+type StartBlockServiceServer interface {
+	// ResolveBlock finds the block of a network at a timestamp.
+	ResolveBlock(context.Context, *ResolveBlockRequest) (*BlockRef, error)
+	// ResolveAll finds the start block of every network at a timestamp.
+	ResolveAll(context.Context, *ResolveAllRequest) (*Params, error)
+	mustEmbedUnimplementedStartBlockServiceServer()
+}
+
+// UnimplementedStartBlockServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStartBlockServiceServer struct{}
+
+func (UnimplementedStartBlockServiceServer) ResolveBlock(context.Context, *ResolveBlockRequest) (*BlockRef, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveBlock not implemented")
+}
+func (UnimplementedStartBlockServiceServer) ResolveAll(context.Context, *ResolveAllRequest) (*Params, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveAll not implemented")
+}
+func (UnimplementedStartBlockServiceServer) mustEmbedUnimplementedStartBlockServiceServer() {}
+func (UnimplementedStartBlockServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeStartBlockServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StartBlockServiceServer will
+// result in compilation errors.
+type UnsafeStartBlockServiceServer interface {
+	mustEmbedUnimplementedStartBlockServiceServer()
+}
+
+func RegisterStartBlockServiceServer(s grpc.ServiceRegistrar, srv StartBlockServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStartBlockServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StartBlockService_ServiceDesc, srv)
+}
+
+func _StartBlockService_ResolveBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StartBlockServiceServer).ResolveBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StartBlockService_ResolveBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StartBlockServiceServer).ResolveBlock(ctx, req.(*ResolveBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StartBlockService_ResolveAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StartBlockServiceServer).ResolveAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StartBlockService_ResolveAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StartBlockServiceServer).ResolveAll(ctx, req.(*ResolveAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StartBlockService_ServiceDesc is the grpc.ServiceDesc for StartBlockService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StartBlockService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "startblock.v1.StartBlockService",
+	HandlerType: (*StartBlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveBlock",
+			Handler:    _StartBlockService_ResolveBlock_Handler,
+		},
+		{
+			MethodName: "ResolveAll",
+			Handler:    _StartBlockService_ResolveAll_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "startblock/v1/startblock.proto",
+}